@@ -10,11 +10,13 @@ import (
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	bs "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
 	cp "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/credentialprovider"
 	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
 	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/metrics"
 	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
 
 	"github.com/labstack/echo/v4"
@@ -25,7 +27,7 @@ import (
 )
 
 const (
-	GG_HEADER = "Gitguardian-Signature"
+	GG_HEADER = gg.SIGNATURE_HEADER
 )
 
 var (
@@ -42,11 +44,24 @@ func main() {
 	hostobjname := flag.String("hostobjname", "", "Integration Host Account object name")
 	dbobjname := flag.String("dbobjname", "", "Integration Database Account object name")
 	appid := flag.String("appid", "", "Integration Host Application ID")
+	ccpurl := flag.String("ccp-url", "", "Central Credential Provider url, ex: https://ccp.example.com; fetch the settings with the CCP REST api instead of the Credential Provider SDK")
+	ccpcert := flag.String("ccp-cert", "", "PEM client certificate authenticating the application to the CCP")
+	ccpkey := flag.String("ccp-key", "", "PEM key of the CCP client certificate")
+	ccpca := flag.String("ccp-ca", "", "PEM CA bundle verifying the CCP certificate, default is the system roots")
 
 	url := flag.String("hmslurl", "https://api.hasmysecretleaked.com", "HMSL url where to send hashes (Used as audience when sending JWT request)")
 	audiencetype := flag.String("hmslaudtype", "hmsl", "Audience type for HMSL JWT request")
 
 	tlsskipverify := flag.Bool("tls-skip-verify", false, "Skip TLS Verify when calling pam (for self-signed cert)")
+	dryrun := flag.Bool("dry-run", false, "Report planned remediations without changing PAM or the database")
+	pruneinterval := flag.Duration("hash-prune-interval", time.Hour, "How often hash versions outside the safe retention policies are removed, 0 disables")
+	webhookmaxskew := flag.Duration("gg-webhook-max-skew", 5*time.Minute, "Max difference between the GG webhook timestamp and the local clock, 0 disables the check")
+	incidentactions := flag.String("gg-incident-actions", bs.DEFAULT_INCIDENT_ACTIONS, "Comma separated GG incident action:behavior pairs, behavior is remediate, close or record")
+	reconcileinterval := flag.Duration("gg-reconcile-interval", 0, "How often GG incidents missed by the webhook are reconciled from the last checkpoint, 0 disables")
+	rotationpollinterval := flag.Duration("rotation-poll-interval", 30*time.Second, "How often the CPM status of requested password changes is checked, 0 disables rotation tracking")
+	rotationtimeout := flag.Duration("rotation-timeout", 10*time.Minute, "Password changes not confirmed by the CPM within the timeout are requested again")
+	rotationmaxattempts := flag.Int("rotation-max-attempts", 3, "Password changes failing every attempt are escalated")
+	rehashafterrotation := flag.Bool("rehash-after-rotation", false, "Save the hash of the new password once the CPM confirms a password change, for platforms without the CPM plugin")
 
 	ver := flag.Bool("version", false, "Print version")
 	debug := flag.Bool("d", false, "Enable debug settings")
@@ -55,10 +70,25 @@ func main() {
 	TLS_SKIP_VERIFY = *tlsskipverify
 	DEBUG = *debug
 
+	incidentactionmap, err := bs.ParseIncidentActions(*incidentactions)
+	if err != nil {
+		log.Fatalf("failed to parse -gg-incident-actions: %s", err)
+	}
+
 	// Fetch the Integration Host settings from the Credential Provider
-	cpclient := cp.Client{}
+	cpclient, err := cp.NewClient(cp.ClientConfig{
+		CCPURL:        *ccpurl,
+		CertFile:      *ccpcert,
+		KeyFile:       *ccpkey,
+		CAFile:        *ccpca,
+		TLSSkipVerify: TLS_SKIP_VERIFY,
+	})
+	if err != nil {
+		log.Fatalf("failed to create credential provider client: %s", err)
+	}
 	hostattrs := []string{
 		"PassProps.APIKey",
+		"PassProps.AdminAPIKey",
 		"PassProps.Port",
 		"PassProps.GitGuardianAPIURL",
 		"PassProps.GitGuardianAPIToken",
@@ -68,9 +98,10 @@ func main() {
 		"PassProps.PAMUser",
 		"PassProps.PAMPassword",
 		"PassProps.PendingSafename",
+		"PassProps.HashExportKey",
 	}
 	hostprops := cp.NewProperties("PASSWORD", *safe, *appid, *hostobjname, hostattrs)
-	hostpropvals, err := cpclient.FetchProperties(hostprops)
+	hostpropvals, err := cpclient.FetchProperties(context.Background(), hostprops)
 	if err != nil {
 		log.Fatalf("%s", err.Error())
 	}
@@ -84,7 +115,7 @@ func main() {
 		"PassProps.DSN",
 	}
 	dbprops := cp.NewProperties("PASSWORD", *safe, *appid, *dbobjname, dbattrs)
-	dbpropvals, err := cpclient.FetchProperties(dbprops)
+	dbpropvals, err := cpclient.FetchProperties(context.Background(), dbprops)
 	if err != nil {
 		log.Fatalf("%s", err.Error())
 	}
@@ -103,12 +134,14 @@ func main() {
 		User:            hostpropvals.Attributes["PassProps.PAMUser"],
 		Pass:            hostpropvals.Attributes["PassProps.PAMPassword"],
 		TLS_SKIP_VERIFY: TLS_SKIP_VERIFY,
+		Transport:       metrics.InstrumentTransport(metrics.PAMRequests, metrics.PAMRequestDuration),
 	}
 
 	e := echo.New()
 
 	// Log all requests
 	e.Use(middleware.Logger())
+	e.Use(metrics.EchoMiddleware())
 	if *ver {
 		e.Logger.Printf("Version: %s\n", version)
 		os.Exit(0)
@@ -141,7 +174,7 @@ func main() {
 			return len(ggsig) == 0
 		},
 		Validator: func(key string, c echo.Context) (bool, error) {
-			return GGValidator(key, c, ggwebhooktoken)
+			return GGValidator(key, c, ggwebhooktoken, *webhookmaxskew)
 		},
 	}))
 
@@ -155,6 +188,11 @@ func main() {
 			return len(ggsig) > 0
 		},
 		Validator: func(key string, c echo.Context) (bool, error) {
+			adminapikey, ok := hostpropvals.Attributes["PassProps.AdminAPIKey"]
+			if ok && len(adminapikey) > 0 && key == adminapikey {
+				c.Set(bs.ADMIN_CONTEXT_KEY, true)
+				return true, nil
+			}
 			brimstoneapikey, ok := hostpropvals.Attributes["PassProps.APIKey"]
 			return (ok && key == brimstoneapikey), nil
 		},
@@ -171,31 +209,63 @@ func main() {
 
 	ctx := context.TODO()
 
-	clientWithResponses, errClient := hmsl.NewClientAuthenticateWithGitGuardian(ctx, url, audiencetype, &ggapiurl, &ggapitoken)
+	clientWithResponses, errClient := hmsl.NewClientAuthenticateWithGitGuardian(ctx, url, audiencetype, &ggapiurl, &ggapitoken,
+		hmsl.WithHTTPClient(metrics.InstrumentedClient(metrics.HMSLRequests, metrics.HMSLRequestDuration)))
 	if errClient != nil {
 		e.Logger.Fatalf("failed to create HMSL client: %s", errClient)
 	}
 
+	ggclient, errGGClient := gg.NewClientWithToken(ggapiurl, ggapitoken)
+	if errGGClient != nil {
+		e.Logger.Fatalf("failed to create GG client: %s", errGGClient)
+	}
+
 	br := bs.Brimstone{
-		Db:         db,
-		HMSLClient: clientWithResponses,
-		PAMConfig:  &pamconfig,
+		Db:                db,
+		HMSLClient:        clientWithResponses,
+		PAMSessions:       pam.NewSessionManager(pamconfig),
+		Scheduler:         bs.NewScheduler(),
+		DryRun:            *dryrun,
+		PruneInterval:     *pruneinterval,
+		WebhookMaxSkew:    *webhookmaxskew,
+		GGClient:          ggclient,
+		IncidentActions:   incidentactionmap,
+		ReconcileInterval: *reconcileinterval,
+
+		RotationPollInterval: *rotationpollinterval,
+		RotationTimeout:      *rotationtimeout,
+		RotationMaxAttempts:  *rotationmaxattempts,
+		RehashAfterRotation:  *rehashafterrotation,
+
+		HashExportKey: []byte(hostpropvals.Attributes["PassProps.HashExportKey"]),
 	}
 
 	bs.RegisterHandlers(e, br)
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
 
 	if initdbErr := br.InitializeDb(); initdbErr != nil {
 		e.Logger.Fatalf("failed to initialize database: %s", initdbErr)
 	}
 
+	if metricsErr := metrics.RegisterStoredHashes(br.CountHashesBySafe); metricsErr != nil {
+		e.Logger.Fatalf("failed to register metrics: %s", metricsErr)
+	}
+
+	if schedulerErr := br.StartScheduler(); schedulerErr != nil {
+		e.Logger.Fatalf("failed to start scan scheduler: %s", schedulerErr)
+	}
+
 	e.Logger.Fatal(e.Start(net.JoinHostPort("0.0.0.0", hostpropvals.Attributes["PassProps.Port"])))
 }
 
-func GGValidator(ggsig string, c echo.Context, webhooktoken string) (bool, error) {
-	ggts := c.Request().Header.Get("timestamp")
+func GGValidator(ggsig string, c echo.Context, webhooktoken string, maxskew time.Duration) (bool, error) {
+	ggts := c.Request().Header.Get(gg.TIMESTAMP_HEADER)
 	if !strings.HasPrefix(ggsig, "sha256=") {
 		return false, fmt.Errorf("bad signature")
 	}
+	if err := gg.ValidateGGTimestamp(ggts, maxskew, time.Now()); err != nil {
+		return false, fmt.Errorf("bad gg request: %s", err.Error())
+	}
 	bodyBytes, bbErr := io.ReadAll(c.Request().Body)
 	c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // need to put the bytes reader back on the Body
 	if bbErr != nil {
@@ -10,11 +10,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
 	bs "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
 	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
 	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/metrics"
 	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
 
 	"github.com/labstack/echo/v4"
@@ -28,7 +30,7 @@ import (
 )
 
 const (
-	GG_HEADER = "Gitguardian-Signature"
+	GG_HEADER = gg.SIGNATURE_HEADER
 )
 
 var (
@@ -43,6 +45,7 @@ type config struct {
 	GgApiToken     string `env:"GG_API_TOKEN,unset"`
 	GgWebhookToken string `env:"GG_WEBHOOK_TOKEN,required,unset"`
 	ApiKey         string `env:"BRIMSTONE_API_KEY,unset"`
+	AdminApiKey    string `env:"BRIMSTONE_ADMIN_API_KEY,unset"`
 
 	DbUrl string `env:"DB_URL,required,unset"`
 	Port  uint16 `env:"PORT" envDefault:"9191"`
@@ -63,6 +66,11 @@ func main() {
 
 	DEBUG = *debug
 
+	incidentactions, err := bs.ParseIncidentActions(cfg.GgIncidentActions)
+	if err != nil {
+		e.Logger.Fatalf("failed to parse GG_INCIDENT_ACTIONS: %s", err)
+	}
+
 	pamconfig := pam.Config{
 		IDTenantURL:     cfg.IdTenantUrl,
 		PCloudURL:       cfg.PcloudUrl,
@@ -71,6 +79,7 @@ func main() {
 		User:            cfg.PamUser,
 		Pass:            cfg.PamPass,
 		TLS_SKIP_VERIFY: cfg.TlsSkipVerify,
+		Transport:       metrics.InstrumentTransport(metrics.PAMRequests, metrics.PAMRequestDuration),
 	}
 
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -82,6 +91,7 @@ func main() {
 
 	// Log all requests
 	e.Use(middleware.Logger())
+	e.Use(metrics.EchoMiddleware())
 	if *ver {
 		e.Logger.Printf("Version: %s\n", version)
 		os.Exit(0)
@@ -112,6 +122,10 @@ func main() {
 		},
 		Validator: func(key string, c echo.Context) (bool, error) {
 			cfg := c.Get("config").(config)
+			if len(cfg.AdminApiKey) > 0 && key == cfg.AdminApiKey {
+				c.Set(bs.ADMIN_CONTEXT_KEY, true)
+				return true, nil
+			}
 			return key == cfg.ApiKey, nil
 		},
 	}))
@@ -135,33 +149,65 @@ func main() {
 	}
 
 	ctx := context.TODO()
-	clientWithResponses, errClient := hmsl.NewClientAuthenticateWithGitGuardian(ctx, &cfg.HmslUrl, &cfg.AudienceType, &cfg.GgApiUrl, &cfg.GgApiToken)
+	clientWithResponses, errClient := hmsl.NewClientAuthenticateWithGitGuardian(ctx, &cfg.HmslUrl, &cfg.AudienceType, &cfg.GgApiUrl, &cfg.GgApiToken,
+		hmsl.WithHTTPClient(metrics.InstrumentedClient(metrics.HMSLRequests, metrics.HMSLRequestDuration)))
 	if errClient != nil {
 		e.Logger.Fatalf("failed to create HMSL client: %s", errClient)
 	}
 
+	ggclient, errGGClient := gg.NewClientWithToken(cfg.GgApiUrl, cfg.GgApiToken)
+	if errGGClient != nil {
+		e.Logger.Fatalf("failed to create GG client: %s", errGGClient)
+	}
+
 	br := bs.Brimstone{
-		Db:         db,
-		HMSLClient: clientWithResponses,
-		PAMConfig:  &pamconfig,
+		Db:                db,
+		HMSLClient:        clientWithResponses,
+		PAMSessions:       pam.NewSessionManager(pamconfig),
+		Scheduler:         bs.NewScheduler(),
+		DryRun:            cfg.DryRun,
+		PruneInterval:     cfg.HashPruneInterval,
+		WebhookMaxSkew:    cfg.GgWebhookMaxSkew,
+		GGClient:          ggclient,
+		IncidentActions:   incidentactions,
+		ReconcileInterval: cfg.GgReconcileInterval,
+
+		RotationPollInterval: cfg.RotationPollInterval,
+		RotationTimeout:      cfg.RotationTimeout,
+		RotationMaxAttempts:  cfg.RotationMaxAttempts,
+		RehashAfterRotation:  cfg.RehashAfterRotation,
+
+		HashExportKey: []byte(cfg.HashExportKey),
 	}
 
 	bs.RegisterHandlers(e, br)
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
 
 	if initdbErr := br.InitializeDb(); initdbErr != nil {
 		e.Logger.Fatalf("failed to initialize database: %s", initdbErr)
 	}
 
+	if metricsErr := metrics.RegisterStoredHashes(br.CountHashesBySafe); metricsErr != nil {
+		e.Logger.Fatalf("failed to register metrics: %s", metricsErr)
+	}
+
+	if schedulerErr := br.StartScheduler(); schedulerErr != nil {
+		e.Logger.Fatalf("failed to start scan scheduler: %s", schedulerErr)
+	}
+
 	server_addr := net.JoinHostPort("0.0.0.0", strconv.Itoa(int(cfg.Port)))
 	e.Logger.Fatal(e.Start(server_addr))
 }
 
 func GGValidator(ggsig string, c echo.Context) (bool, error) {
 	cfg := c.Get("config").(config)
-	ggts := c.Request().Header.Get("timestamp")
+	ggts := c.Request().Header.Get(gg.TIMESTAMP_HEADER)
 	if !strings.HasPrefix(ggsig, "sha256=") {
 		return false, fmt.Errorf("bad signature")
 	}
+	if err := gg.ValidateGGTimestamp(ggts, cfg.GgWebhookMaxSkew, time.Now()); err != nil {
+		return false, fmt.Errorf("bad gg request: %s", err.Error())
+	}
 	bodyBytes, bbErr := io.ReadAll(c.Request().Body)
 	c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // need to put the bytes reader back on the Body
 	if bbErr != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
@@ -20,6 +21,11 @@ func main() {
 	hostobjname := flag.String("hostobjname", "", "Integration Host Account object name")
 	dbobjname := flag.String("dbobjname", "", "Integration Database Account object name")
 	appid := flag.String("appid", "", "Integration Host Application ID")
+	ccpurl := flag.String("ccp-url", "", "Central Credential Provider url, use the CCP REST api instead of the Credential Provider SDK")
+	ccpcert := flag.String("ccp-cert", "", "PEM client certificate authenticating the application to the CCP")
+	ccpkey := flag.String("ccp-key", "", "PEM key of the CCP client certificate")
+	ccpca := flag.String("ccp-ca", "", "PEM CA bundle verifying the CCP certificate")
+	tlsskipverify := flag.Bool("tls-skip-verify", false, "Skip TLS Verify when calling the CCP (for self-signed cert)")
 
 	debug := flag.Bool("d", false, "Enable debug settings")
 	ver := flag.Bool("version", false, "Print version")
@@ -31,8 +37,18 @@ func main() {
 	}
 
 	DEBUG = *debug
+	TLS_SKIP_VERIFY = *tlsskipverify
 
-	client := credentialprovider.Client{}
+	client, err := credentialprovider.NewClient(credentialprovider.ClientConfig{
+		CCPURL:        *ccpurl,
+		CertFile:      *ccpcert,
+		KeyFile:       *ccpkey,
+		CAFile:        *ccpca,
+		TLSSkipVerify: TLS_SKIP_VERIFY,
+	})
+	if err != nil {
+		log.Fatalf("%s", err.Error())
+	}
 
 	hostattrs := []string{
 		"PassProps.APIKey",
@@ -47,7 +63,7 @@ func main() {
 		"PassProps.PendingSafename",
 	}
 	hostprops := credentialprovider.NewProperties("PASSWORD", *safe, *appid, *hostobjname, hostattrs)
-	hostpropvals, err := client.FetchProperties(hostprops)
+	hostpropvals, err := client.FetchProperties(context.Background(), hostprops)
 	if err != nil {
 		log.Fatalf("%s", err.Error())
 	}
@@ -59,7 +75,7 @@ func main() {
 		"PassProps.DSN",
 	}
 	dbprops := credentialprovider.NewProperties("PASSWORD", *safe, *appid, *dbobjname, dbattrs)
-	dbpropvals, err := client.FetchProperties(dbprops)
+	dbpropvals, err := client.FetchProperties(context.Background(), dbprops)
 	if err != nil {
 		log.Fatalf("%s", err.Error())
 	}
@@ -0,0 +1,58 @@
+//go:build credentialprovider
+
+package main
+
+import (
+	"context"
+	"errors"
+
+	cp "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/credentialprovider"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// Credential Provider attribute holding the account password
+const CP_PASSWORD_ATTRIBUTE = "Password"
+
+func init() {
+	passwordSources[PASSWORD_SOURCE_CREDENTIALPROVIDER] = func(cfg config, client *pam.Client) (PasswordRetriever, error) {
+		if cfg.AppID == "" {
+			return nil, errors.New("HAILSTONE_APP_ID is required with the credential provider")
+		}
+		retriever := CPRetriever{AppID: cfg.AppID}
+		if cfg.CCPURL == "" {
+			// fails when built without the sdk
+			if _, err := cp.NewClient(cp.ClientConfig{}); err != nil {
+				return nil, err
+			}
+		} else {
+			ccp, err := cp.NewCCPClient(cp.ClientConfig{
+				CCPURL:        cfg.CCPURL,
+				CertFile:      cfg.CCPCert,
+				KeyFile:       cfg.CCPKey,
+				CAFile:        cfg.CCPCA,
+				TLSSkipVerify: cfg.TlsSkipVerify,
+			})
+			if err != nil {
+				return nil, err
+			}
+			retriever.CCP = ccp
+		}
+		return retriever, nil
+	}
+}
+
+// CPRetriever - retrieves passwords with the Credential Provider installed on the host, or the
+// Central Credential Provider when CCP is set, as the application id; the application must be a
+// member of the safes with the retrieve permission
+type CPRetriever struct {
+	AppID string
+	CCP   *cp.CCPClient // shared, safe for concurrent use
+}
+
+func (r CPRetriever) RetrievePassword(ctx context.Context, account pam.Account) (string, error) {
+	props := cp.NewProperties("PASSWORD", account.SafeName, r.AppID, account.Name, []string{CP_PASSWORD_ATTRIBUTE})
+	if r.CCP != nil {
+		return r.CCP.FetchProperty(ctx, props, CP_PASSWORD_ATTRIBUTE)
+	}
+	return fetchSDKPassword(ctx, props)
+}
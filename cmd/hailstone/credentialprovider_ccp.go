@@ -0,0 +1,14 @@
+//go:build credentialprovider && (!cgo || ccp)
+
+package main
+
+import (
+	"context"
+
+	cp "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/credentialprovider"
+)
+
+// built without the sdk, HAILSTONE_CCP_URL is required
+func fetchSDKPassword(ctx context.Context, props cp.Properties) (string, error) {
+	return "", cp.ErrSDKUnavailable
+}
@@ -0,0 +1,17 @@
+//go:build credentialprovider && cgo && !ccp
+
+package main
+
+import (
+	"context"
+
+	cp "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/credentialprovider"
+)
+
+func fetchSDKPassword(ctx context.Context, props cp.Properties) (string, error) {
+	// a client per request, the client keeps the request and response handles
+	client := cp.Client{}
+	defer client.FreeRequest()
+	defer client.FreeResponse()
+	return client.FetchProperty(ctx, props, CP_PASSWORD_ATTRIBUTE)
+}
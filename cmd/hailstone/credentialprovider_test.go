@@ -0,0 +1,85 @@
+//go:build credentialprovider && (!cgo || ccp)
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cp "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/credentialprovider"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCCPStub - a CCP returning the password of the accounts of safe1 to the Hailstone
+// application; returns its url and the CA file trusting it
+func newCCPStub(t *testing.T) (string, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, cp.CCP_ACCOUNTS_PATH, r.URL.Path)
+		query := r.URL.Query()
+		if query.Get("AppID") != "Hailstone" || query.Get("Safe") != "safe1" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(cp.CCPError{ErrorCode: "APPAP306E", ErrorMsg: "Authentication failed"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"Content": "pass-" + query.Get("Object")})
+	}))
+	t.Cleanup(server.Close)
+
+	cafile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(cafile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	return server.URL, cafile
+}
+
+func TestNewPasswordRetrieverCredentialProvider(t *testing.T) {
+	ccpurl, cafile := newCCPStub(t)
+	tests := []struct {
+		name string
+		cfg  config
+		ccp  bool
+		err  string
+	}{
+		{name: "no app id", cfg: config{CCPURL: ccpurl, CCPCA: cafile}, err: "HAILSTONE_APP_ID is required"},
+		{name: "sdk not built in", cfg: config{AppID: "Hailstone"}, err: cp.ErrSDKUnavailable.Error()},
+		{name: "ccp", cfg: config{AppID: "Hailstone", CCPURL: ccpurl, CCPCA: cafile}, ccp: true},
+		{name: "ccp certificate without key", cfg: config{AppID: "Hailstone", CCPURL: ccpurl, CCPCert: "client.pem"}, err: "certificate and key are both required"},
+		{name: "ccp missing ca", cfg: config{AppID: "Hailstone", CCPURL: ccpurl, CCPCA: filepath.Join(t.TempDir(), "missing.pem")}, err: "unable to read ccp ca"},
+	}
+	for i := 0; i < len(tests); i++ {
+		retriever, err := NewPasswordRetriever(PASSWORD_SOURCE_CREDENTIALPROVIDER, tests[i].cfg, nil)
+		if tests[i].err != "" {
+			assert.ErrorContains(t, err, tests[i].err, tests[i].name)
+			continue
+		}
+		assert.NoError(t, err, tests[i].name)
+		cpretriever, ok := retriever.(CPRetriever)
+		assert.True(t, ok, tests[i].name)
+		assert.Equal(t, "Hailstone", cpretriever.AppID)
+		assert.Equal(t, tests[i].ccp, cpretriever.CCP != nil, tests[i].name)
+	}
+}
+
+func TestCPRetrieverCCP(t *testing.T) {
+	ccpurl, cafile := newCCPStub(t)
+	retriever, err := NewPasswordRetriever(PASSWORD_SOURCE_CREDENTIALPROVIDER, config{AppID: "Hailstone", CCPURL: ccpurl, CCPCA: cafile}, nil)
+	assert.NoError(t, err)
+
+	// the account is queried by safe and account name
+	password, err := retriever.RetrievePassword(context.Background(), pam.Account{ID: "1_1", Name: "root-db1", SafeName: "safe1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pass-root-db1", password)
+
+	// the application is not a member of the safe
+	_, err = retriever.RetrievePassword(context.Background(), pam.Account{ID: "2_1", Name: "root-db2", SafeName: "safe2"})
+	assert.ErrorContains(t, err, "APPAP306E")
+
+	// the sdk is not built in without cgo, or with -tags ccp
+	_, err = CPRetriever{AppID: "Hailstone"}.RetrievePassword(context.Background(), pam.Account{ID: "1_1", Name: "root-db1", SafeName: "safe1"})
+	assert.ErrorIs(t, err, cp.ErrSDKUnavailable)
+}
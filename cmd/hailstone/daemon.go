@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// path of the daemon health endpoint
+const HEALTH_PATH = "/healthz"
+
+// Health - status of the daemon sync cycles, served on the health endpoint
+type Health struct {
+	mu        sync.Mutex
+	cycles    int
+	lastsync  time.Time
+	lasterror string
+	failures  int
+}
+
+// HealthResponse - body of the health endpoint
+type HealthResponse struct {
+	Status    string     `json:"status"` // starting, ok or error when the last cycle could not run
+	Cycles    int        `json:"cycles"`
+	LastSync  *time.Time `json:"last_sync,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	Failures  int        `json:"failures"` // accounts and safes not synced by the last cycle
+}
+
+func (h *Health) record(summary SyncSummary, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cycles++
+	h.lastsync = summary.Started
+	h.lasterror = errorString(err)
+	h.failures = len(summary.Failures)
+}
+
+// ServeHTTP - 200 until a sync cycle fails to run, then 503 until one runs again
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	rsp := HealthResponse{Status: "ok", Cycles: h.cycles, LastError: h.lasterror, Failures: h.failures}
+	if h.cycles == 0 {
+		rsp.Status = "starting"
+	} else {
+		lastsync := h.lastsync
+		rsp.LastSync = &lastsync
+	}
+	h.mu.Unlock()
+
+	code := http.StatusOK
+	if rsp.LastError != "" {
+		rsp.Status = "error"
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(rsp)
+}
+
+// RunDaemon syncs every interval, plus a random delay up to jitter, until the context is
+// cancelled (SIGTERM); a cancelled sync stops its requests, the accounts already synced are
+// kept in the state. Each cycle logs its counts, in total and by safe, as json.
+func RunDaemon(ctx context.Context, syncer *Syncer, interval time.Duration, jitter time.Duration, healthaddr string) {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+
+	health := &Health{}
+	if healthaddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(HEALTH_PATH, health)
+		server := &http.Server{Addr: healthaddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			err := server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("health endpoint failed: %s", err.Error())
+			}
+		}()
+		defer func() {
+			shutdownctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownctx)
+		}()
+	}
+
+	slog.Info("hailstone daemon started", "interval", interval.String(), "jitter", jitter.String(), "health", healthaddr)
+	for cycle := 1; ctx.Err() == nil; cycle++ {
+		summary, err := syncer.Sync(ctx)
+		health.record(summary, err)
+		logCycle(cycle, summary, err)
+
+		wait := interval
+		if jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitter)))
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait):
+		}
+	}
+	slog.Info("hailstone daemon stopped")
+}
+
+// logCycle - structured log of the cycle counts, in total and by safe
+func logCycle(cycle int, summary SyncSummary, err error) {
+	if err != nil {
+		slog.Error("sync cycle failed", "cycle", cycle, "error", err.Error())
+		return
+	}
+	slog.Info("sync cycle", "cycle", cycle, "duration", summary.Duration.String(), "listed", summary.Listed, "selected", summary.Selected,
+		"changed", summary.Changed, "synced", summary.Synced, "deleted", summary.Deleted, "failures", len(summary.Failures))
+
+	safenames := make([]string, 0, len(summary.Safes))
+	for safename := range summary.Safes {
+		safenames = append(safenames, safename)
+	}
+	sort.Strings(safenames)
+	for i := 0; i < len(safenames); i++ {
+		safe := summary.Safes[safenames[i]]
+		slog.Info("sync safe", "cycle", cycle, "safename", safe.Safename, "selected", safe.Selected, "changed", safe.Changed,
+			"synced", safe.Synced, "deleted", safe.Deleted, "failed", safe.Failed)
+	}
+	if len(summary.Failures) > 0 {
+		printFailures(summary.Failures)
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
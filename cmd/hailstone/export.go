@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
+)
+
+// WriteExport writes the hash batches to a new signed JSONL export in the directory, named
+// after its creation time, for vaults that cannot reach brimstone; the file is imported with
+// POST /v1/hashes/import. Returns the path of the export.
+func WriteExport(dir string, batches []brimstone.HashBatch, key []byte, now time.Time) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("hailstone-%s.jsonl", now.UTC().Format("20060102T150405.000Z")))
+	tmp, err := os.CreateTemp(dir, ".hailstone-*.jsonl")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	// the export is only visible once fully written
+	if err := brimstone.WriteHashExport(tmp, batches, key, now); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
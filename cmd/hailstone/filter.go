@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// prefix of the patterns that are regular expressions instead of globs
+const REGEX_PATTERN_PREFIX = "re:"
+
+// Pattern - glob, ex: prod-*, or regular expression when prefixed with re:, ex: re:^prod-[0-9]+$
+type Pattern struct {
+	glob  string
+	regex *regexp.Regexp
+}
+
+// ParsePattern - parses a glob or re: prefixed regular expression
+func ParsePattern(pattern string) (Pattern, error) {
+	if strings.HasPrefix(pattern, REGEX_PATTERN_PREFIX) {
+		regex, err := regexp.Compile(strings.TrimPrefix(pattern, REGEX_PATTERN_PREFIX))
+		if err != nil {
+			return Pattern{}, fmt.Errorf("invalid regular expression, %s: %s", pattern, err.Error())
+		}
+		return Pattern{regex: regex}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return Pattern{}, fmt.Errorf("invalid glob, %s: %s", pattern, err.Error())
+	}
+	return Pattern{glob: pattern}, nil
+}
+
+// ParsePatterns - parses a comma separated list of patterns
+func ParsePatterns(list string) ([]Pattern, error) {
+	var patterns []Pattern
+	items := splitList(list)
+	for i := 0; i < len(items); i++ {
+		pattern, err := ParsePattern(items[i])
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+func (p Pattern) Match(value string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(value)
+	}
+	matched, _ := path.Match(p.glob, value)
+	return matched
+}
+
+func (p Pattern) String() string {
+	if p.regex != nil {
+		return REGEX_PATTERN_PREFIX + p.regex.String()
+	}
+	return p.glob
+}
+
+// PropertyFilter - account property matching, or with != not matching, the pattern,
+// ex: address=*.prod.example.com or userName!=root
+type PropertyFilter struct {
+	Property string
+	Negate   bool
+	Pattern  Pattern
+}
+
+// ParsePropertyFilters - parses a comma separated list of property filters
+func ParsePropertyFilters(list string) ([]PropertyFilter, error) {
+	var filters []PropertyFilter
+	items := splitList(list)
+	for i := 0; i < len(items); i++ {
+		var filter PropertyFilter
+		property, pattern, ok := strings.Cut(items[i], "=")
+		if !ok || property == "" {
+			return nil, fmt.Errorf("invalid property filter, %s, expected property=pattern or property!=pattern", items[i])
+		}
+		if strings.HasSuffix(property, "!") {
+			filter.Negate = true
+			property = strings.TrimSuffix(property, "!")
+		}
+		if _, ok := accountProperty(pam.Account{}, property); !ok {
+			return nil, fmt.Errorf("invalid property filter, %s, unknown property, %s", items[i], property)
+		}
+		filter.Property = property
+		p, err := ParsePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.Pattern = p
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func (f PropertyFilter) Match(account pam.Account) bool {
+	value, _ := accountProperty(account, f.Property)
+	return f.Pattern.Match(value) != f.Negate
+}
+
+func (f PropertyFilter) String() string {
+	op := "="
+	if f.Negate {
+		op = "!="
+	}
+	return f.Property + op + f.Pattern.String()
+}
+
+// accountProperty - value of the account property usable in filters
+func accountProperty(account pam.Account, property string) (string, bool) {
+	switch property {
+	case "name":
+		return account.Name, true
+	case "address":
+		return account.Address, true
+	case "userName":
+		return account.UserName, true
+	case "platformId":
+		return account.PlatformId, true
+	case "safeName":
+		return account.SafeName, true
+	case "secretType":
+		return account.SecretType, true
+	}
+	return "", false
+}
+
+// Filter - selects the accounts hailstone syncs, before any password is retrieved. An account
+// is selected when its safe and platform match an include pattern, if any, and no exclude
+// pattern, and it matches every property filter.
+type Filter struct {
+	IncludeSafes     []Pattern
+	ExcludeSafes     []Pattern
+	IncludePlatforms []Pattern
+	ExcludePlatforms []Pattern
+	Properties       []PropertyFilter
+}
+
+// NewFilter - parses the comma separated lists of patterns and property filters
+func NewFilter(includesafes, excludesafes, includeplatforms, excludeplatforms, properties string) (Filter, error) {
+	var filter Filter
+	var err error
+	if filter.IncludeSafes, err = ParsePatterns(includesafes); err != nil {
+		return filter, err
+	}
+	if filter.ExcludeSafes, err = ParsePatterns(excludesafes); err != nil {
+		return filter, err
+	}
+	if filter.IncludePlatforms, err = ParsePatterns(includeplatforms); err != nil {
+		return filter, err
+	}
+	if filter.ExcludePlatforms, err = ParsePatterns(excludeplatforms); err != nil {
+		return filter, err
+	}
+	if filter.Properties, err = ParsePropertyFilters(properties); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// Match - whether the account is selected, or the reason it is excluded
+func (f Filter) Match(account pam.Account) (bool, string) {
+	if len(f.IncludeSafes) > 0 && !matchAny(f.IncludeSafes, account.SafeName) {
+		return false, "safe not included"
+	}
+	if matchAny(f.ExcludeSafes, account.SafeName) {
+		return false, "safe excluded"
+	}
+	if len(f.IncludePlatforms) > 0 && !matchAny(f.IncludePlatforms, account.PlatformId) {
+		return false, "platform not included"
+	}
+	if matchAny(f.ExcludePlatforms, account.PlatformId) {
+		return false, "platform excluded"
+	}
+	for i := 0; i < len(f.Properties); i++ {
+		if !f.Properties[i].Match(account) {
+			return false, "property filter, " + f.Properties[i].String()
+		}
+	}
+	return true, ""
+}
+
+// SelectsSafe - true when the safe include and exclude patterns select the safe
+func (f Filter) SelectsSafe(safename string) bool {
+	if len(f.IncludeSafes) > 0 && !matchAny(f.IncludeSafes, safename) {
+		return false
+	}
+	return !matchAny(f.ExcludeSafes, safename)
+}
+
+// Select - the selected accounts, and the number of excluded accounts by reason
+func (f Filter) Select(accounts []pam.Account) ([]pam.Account, map[string]int) {
+	var selected []pam.Account
+	excluded := make(map[string]int)
+	for i := 0; i < len(accounts); i++ {
+		ok, reason := f.Match(accounts[i])
+		if !ok {
+			excluded[reason]++
+			continue
+		}
+		selected = append(selected, accounts[i])
+	}
+	return selected, excluded
+}
+
+func (f Filter) String() string {
+	var parts []string
+	add := func(name string, patterns []Pattern) {
+		if len(patterns) == 0 {
+			return
+		}
+		var values []string
+		for i := 0; i < len(patterns); i++ {
+			values = append(values, patterns[i].String())
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, strings.Join(values, ",")))
+	}
+	add("include safes", f.IncludeSafes)
+	add("exclude safes", f.ExcludeSafes)
+	add("include platforms", f.IncludePlatforms)
+	add("exclude platforms", f.ExcludePlatforms)
+	for i := 0; i < len(f.Properties); i++ {
+		parts = append(parts, "property: "+f.Properties[i].String())
+	}
+	if len(parts) == 0 {
+		return "every account"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func matchAny(patterns []Pattern, value string) bool {
+	for i := 0; i < len(patterns); i++ {
+		if patterns[i].Match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList - comma separated list, blank items ignored
+func splitList(list string) []string {
+	var items []string
+	fields := strings.Split(list, ",")
+	for i := 0; i < len(fields); i++ {
+		if item := strings.TrimSpace(fields[i]); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// PrintSelection - dry-run listing of the effective filter, the selected accounts, whether they
+// are hashed by the next sync, and the number of excluded accounts by reason
+func PrintSelection(w io.Writer, filter Filter, selected []pam.Account, tosync []pam.Account, excluded map[string]int) {
+	changed := make(map[string]bool, len(tosync))
+	for i := 0; i < len(tosync); i++ {
+		changed[tosync[i].ID] = true
+	}
+
+	fmt.Fprintf(w, "Filter: %s\n\n", filter)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SAFE\tPLATFORM\tACCOUNT ID\tNAME\tSYNC")
+	for i := 0; i < len(selected); i++ {
+		sync := "unchanged"
+		if changed[selected[i].ID] {
+			sync = "hash"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", selected[i].SafeName, selected[i].PlatformId, selected[i].ID, selected[i].Name, sync)
+	}
+	tw.Flush()
+
+	total := 0
+	reasons := make([]string, 0, len(excluded))
+	for reason, count := range excluded {
+		reasons = append(reasons, reason)
+		total += count
+	}
+	sort.Strings(reasons)
+	fmt.Fprintf(w, "\nSelected: %d, to hash: %d, excluded: %d\n", len(selected), len(tosync), total)
+	for i := 0; i < len(reasons); i++ {
+		fmt.Fprintf(w, "  %s: %d\n", reasons[i], excluded[reasons[i]])
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		match   bool
+		err     bool
+	}{
+		{pattern: "prod-*", value: "prod-app1", match: true},
+		{pattern: "prod-*", value: "test-app1", match: false},
+		{pattern: "app?", value: "app1", match: true},
+		{pattern: "app?", value: "app12", match: false},
+		{pattern: "re:^app[0-9]+$", value: "app12", match: true},
+		{pattern: "re:^app[0-9]+$", value: "app1x", match: false},
+		// a regular expression is not anchored, a glob is
+		{pattern: "re:prod", value: "eu-prod-1", match: true},
+		{pattern: "prod", value: "eu-prod-1", match: false},
+		// re: only prefixes a regular expression, the glob matches it literally
+		{pattern: "^app[0-9]+$", value: "app1", match: false},
+		{pattern: "re:app[", err: true},
+		{pattern: "app[", err: true},
+	}
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		pattern, err := ParsePattern(tt.pattern)
+		if tt.err {
+			assert.Error(t, err, tt.pattern)
+			continue
+		}
+		assert.NoError(t, err, tt.pattern)
+		assert.Equal(t, tt.match, pattern.Match(tt.value), "%s matching %s", tt.pattern, tt.value)
+		assert.Equal(t, tt.pattern, pattern.String())
+	}
+}
+
+func TestParsePropertyFilters(t *testing.T) {
+	account := pam.Account{Name: "root-db1", Address: "db1.prod.example.com", UserName: "root", PlatformId: "UnixSSH", SafeName: "prod-db", SecretType: "password"}
+	tests := []struct {
+		filters string
+		match   bool
+		err     string
+	}{
+		{filters: "address=*.prod.example.com", match: true},
+		{filters: "address=*.test.example.com", match: false},
+		{filters: "userName!=root", match: false},
+		{filters: "userName!=admin", match: true},
+		{filters: "name=re:^root-", match: true},
+		{filters: "name!=re:^root-", match: false},
+		// every filter must match
+		{filters: "safeName=prod-*, secretType=password", match: true},
+		{filters: "safeName=prod-*,platformId=WinDomain", match: false},
+		{filters: "", match: true},
+		{filters: "owner=alice", err: "unknown property, owner"},
+		{filters: "username=root", err: "unknown property, username"},
+		{filters: "address", err: "expected property=pattern"},
+		{filters: "=root", err: "expected property=pattern"},
+		{filters: "address=re:(", err: "invalid regular expression"},
+	}
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		filters, err := ParsePropertyFilters(tt.filters)
+		if tt.err != "" {
+			assert.ErrorContains(t, err, tt.err, tt.filters)
+			continue
+		}
+		assert.NoError(t, err, tt.filters)
+		filter := Filter{Properties: filters}
+		match, _ := filter.Match(account)
+		assert.Equal(t, tt.match, match, tt.filters)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name                               string
+		includesafes, excludesafes         string
+		includeplatforms, excludeplatforms string
+		properties                         string
+		safename, platformid               string
+		match                              bool
+		reason                             string
+	}{
+		{name: "no filter", safename: "safe1", platformid: "UnixSSH", match: true},
+		{name: "included safe", includesafes: "prod-*", safename: "prod-db", platformid: "UnixSSH", match: true},
+		{name: "safe not included", includesafes: "prod-*", safename: "test-db", platformid: "UnixSSH", reason: "safe not included"},
+		{name: "exclude wins over include", includesafes: "prod-*", excludesafes: "prod-breakglass", safename: "prod-breakglass", platformid: "UnixSSH", reason: "safe excluded"},
+		{name: "excluded safe without includes", excludesafes: "re:^test-", safename: "test-db", platformid: "UnixSSH", reason: "safe excluded"},
+		{name: "platform not included", includeplatforms: "UnixSSH,WinDomain", safename: "safe1", platformid: "Oracle", reason: "platform not included"},
+		{name: "excluded platform", includeplatforms: "Win*", excludeplatforms: "*Test*", safename: "safe1", platformid: "WinTestDomain", reason: "platform excluded"},
+		{name: "safe checked before platform", includesafes: "prod-*", excludeplatforms: "UnixSSH", safename: "test-db", platformid: "UnixSSH", reason: "safe not included"},
+		{name: "property filter", includesafes: "prod-*", properties: "platformId!=UnixSSH", safename: "prod-db", platformid: "UnixSSH", reason: "property filter, platformId!=UnixSSH"},
+	}
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		filter, err := NewFilter(tt.includesafes, tt.excludesafes, tt.includeplatforms, tt.excludeplatforms, tt.properties)
+		assert.NoError(t, err, tt.name)
+		match, reason := filter.Match(pam.Account{ID: "1_1", SafeName: tt.safename, PlatformId: tt.platformid})
+		assert.Equal(t, tt.match, match, tt.name)
+		assert.Equal(t, tt.reason, reason, tt.name)
+	}
+
+	_, err := NewFilter("", "", "", "app[", "")
+	assert.ErrorContains(t, err, "invalid glob")
+}
+
+func TestFilterSelect(t *testing.T) {
+	filter, err := NewFilter("prod-*", "prod-breakglass", "", "", "")
+	assert.NoError(t, err)
+	accounts := []pam.Account{
+		{ID: "1_1", SafeName: "prod-db"},
+		{ID: "2_1", SafeName: "prod-breakglass"},
+		{ID: "3_1", SafeName: "test-db"},
+		{ID: "3_2", SafeName: "test-db"},
+	}
+	selected, excluded := filter.Select(accounts)
+	assert.Equal(t, []pam.Account{accounts[0]}, selected)
+	assert.Equal(t, map[string]int{"safe excluded": 1, "safe not included": 2}, excluded)
+	assert.Equal(t, "include safes: prod-*; exclude safes: prod-breakglass", filter.String())
+	assert.Equal(t, "every account", Filter{}.String())
+}
@@ -5,17 +5,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
-	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
 	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
 	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/utils"
 
@@ -28,8 +31,36 @@ var (
 )
 
 type config struct {
-	BrimstoneUrl    string `env:"BRIMSTONE_URL" envDefault:"http://127.0.0.1:9191"`
-	BrimstoneApiKey string `env:"BRIMSTONE_API_KEY,unset"`
+	BrimstoneUrl    string  `env:"BRIMSTONE_URL" envDefault:"http://127.0.0.1:9191"`
+	BrimstoneApiKey string  `env:"BRIMSTONE_API_KEY,unset"`
+	StateFile       string  `env:"HAILSTONE_STATE_FILE"` // incremental sync when set
+	Concurrency     int     `env:"HAILSTONE_CONCURRENCY" envDefault:"4"`
+	RateLimit       float64 `env:"HAILSTONE_RATE_LIMIT" envDefault:"10"` // password retrievals per second, 0 is unlimited
+
+	// comma separated globs, or re: prefixed regular expressions
+	IncludeSafes     string `env:"HAILSTONE_INCLUDE_SAFES"`
+	ExcludeSafes     string `env:"HAILSTONE_EXCLUDE_SAFES"`
+	IncludePlatforms string `env:"HAILSTONE_INCLUDE_PLATFORMS"`
+	ExcludePlatforms string `env:"HAILSTONE_EXCLUDE_PLATFORMS"`
+	AccountFilters   string `env:"HAILSTONE_ACCOUNT_FILTERS"` // comma separated property=pattern or property!=pattern
+
+	// daemon mode when the interval is set
+	Interval   time.Duration `env:"HAILSTONE_INTERVAL" envDefault:"0"`
+	Jitter     time.Duration `env:"HAILSTONE_JITTER" envDefault:"0"`
+	HealthAddr string        `env:"HAILSTONE_HEALTH_ADDR"`
+
+	// offline mode, signed with HASH_EXPORT_KEY
+	ExportDir string `env:"HAILSTONE_EXPORT_DIR"`
+
+	// pam, or credentialprovider with the application id
+	PasswordSource string `env:"HAILSTONE_PASSWORD_SOURCE" envDefault:"pam"`
+	AppID          string `env:"HAILSTONE_APP_ID"`
+
+	// the CCP REST api instead of the credential provider sdk when the url is set
+	CCPURL  string `env:"HAILSTONE_CCP_URL"`
+	CCPCert string `env:"HAILSTONE_CCP_CERT"`
+	CCPKey  string `env:"HAILSTONE_CCP_KEY"`
+	CCPCA   string `env:"HAILSTONE_CCP_CA"`
 
 	brimstone.BaseConfig
 }
@@ -42,6 +73,23 @@ func main() {
 
 	debug := flag.Bool("d", false, "Enable debug settings")
 	ver := flag.Bool("version", false, "Print version")
+	statefile := flag.String("state", cfg.StateFile, "State file of the synced accounts; only accounts changed since the last sync are hashed")
+	full := flag.Bool("full", false, "Hash every account, even when unchanged since the last sync")
+	concurrency := flag.Int("concurrency", cfg.Concurrency, "Number of accounts retrieved and hashed concurrently")
+	ratelimit := flag.Float64("rate-limit", cfg.RateLimit, "Max password retrievals per second, 0 is unlimited")
+	includesafes := flag.String("include-safes", cfg.IncludeSafes, "Comma separated safe name globs, or re: prefixed regular expressions, to sync")
+	excludesafes := flag.String("exclude-safes", cfg.ExcludeSafes, "Comma separated safe name globs, or re: prefixed regular expressions, never synced")
+	includeplatforms := flag.String("include-platforms", cfg.IncludePlatforms, "Comma separated platform id globs, or re: prefixed regular expressions, to sync")
+	excludeplatforms := flag.String("exclude-platforms", cfg.ExcludePlatforms, "Comma separated platform id globs, or re: prefixed regular expressions, never synced")
+	accountfilters := flag.String("account-filters", cfg.AccountFilters, "Comma separated account property filters, property=pattern or property!=pattern, ex: address=*.prod.example.com")
+	dryrun := flag.Bool("dry-run", false, "List the selected accounts without retrieving any password")
+	interval := flag.Duration("interval", cfg.Interval, "Run as a daemon, syncing every interval; 0 syncs once and exits")
+	jitter := flag.Duration("jitter", cfg.Jitter, "Daemon mode, random delay up to jitter added to every interval")
+	healthaddr := flag.String("health-addr", cfg.HealthAddr, "Daemon mode, address of the health endpoint, ex: :8080")
+	passwordsource := flag.String("password-source", cfg.PasswordSource, "Where passwords are retrieved from: pam, or credentialprovider with -appid")
+	appid := flag.String("appid", cfg.AppID, "Application ID used with the credential provider")
+	ccpurl := flag.String("ccp-url", cfg.CCPURL, "Central Credential Provider url, the credential provider uses the CCP REST api instead of the sdk")
+	exportdir := flag.String("export-dir", cfg.ExportDir, "Write the hashes to signed JSONL exports in the directory instead of sending them to brimstone")
 	flag.Parse()
 
 	if *ver {
@@ -51,52 +99,139 @@ func main() {
 
 	DEBUG = *debug
 
+	filter, err := NewFilter(*includesafes, *excludesafes, *includeplatforms, *excludeplatforms, *accountfilters)
+	if err != nil {
+		log.Fatalf("invalid account filters: %s", err.Error())
+	}
+
+	if *exportdir != "" && cfg.HashExportKey == "" {
+		log.Fatalf("HASH_EXPORT_KEY is required to sign hash exports")
+	}
+
 	pamconfig := pam.NewConfig(cfg.IdTenantUrl, cfg.PcloudUrl, cfg.SafeName, cfg.PlatformID, cfg.PamUser, cfg.PamPass, cfg.TlsSkipVerify)
-	client := pam.NewClient(pamconfig.PCloudURL, pamconfig)
-	err := client.RefreshSessionToken()
+	sessions := pam.NewSessionManager(pamconfig)
+	client := sessions.Client()
+	ctx := context.Background()
+	_, err = sessions.Session(ctx)
 	if err != nil {
 		log.Fatalf("failed to fetch session token: %s", err.Error())
 	}
 
-	accounts, err := client.FetchAccounts()
+	cfg.AppID = *appid
+	cfg.CCPURL = *ccpurl
+	retriever, err := NewPasswordRetriever(*passwordsource, cfg, &client)
 	if err != nil {
-		log.Fatalf("failed to fetch all safes: %s", err.Error())
+		log.Fatalf("invalid password source: %s", err.Error())
 	}
 
-	requests := make(map[string]brimstone.HashBatch)
-	for a := range accounts {
-		p, e := client.FetchAccountPassword(accounts[a].ID)
-		if e != nil {
-			log.Printf("error fetching password for account id, %s: %s\n", accounts[a].ID, err.Error())
+	syncer := &Syncer{
+		Config:      cfg,
+		Client:      &client,
+		Retriever:   retriever,
+		Filter:      filter,
+		StateFile:   *statefile,
+		Full:        *full,
+		Concurrency: *concurrency,
+		RateLimit:   *ratelimit,
+		ExportDir:   *exportdir,
+		ExportKey:   []byte(cfg.HashExportKey),
+	}
+	if err := syncer.LoadState(); err != nil {
+		log.Fatalf("failed to read state file, %s: %s", *statefile, err.Error())
+	}
+
+	if *dryrun {
+		if err := syncer.DryRun(ctx, os.Stdout); err != nil {
+			log.Fatalf("failed to list accounts: %s", err.Error())
 		}
+		return
+	}
+
+	if *interval > 0 {
+		ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+		defer stop()
+		RunDaemon(ctx, syncer, *interval, *jitter, *healthaddr)
+		return
+	}
 
-		hmslhash, hhErr := hmsl.ComputeHash(p)
-		if hhErr != nil {
-			log.Printf("Error computing HMSL hash, skipping one: %s", err)
-			continue
+	summary, err := syncer.Sync(ctx)
+	if err != nil {
+		log.Fatalf("sync failed: %s", err.Error())
+	}
+	if len(summary.Failures) > 0 {
+		printFailures(summary.Failures)
+		os.Exit(1)
+	}
+}
+
+// FetchBrimstoneAccounts - the accounts with hashes in the brimstone inventory, by account id,
+// paging through GET /v1/hashes
+func FetchBrimstoneAccounts(cfg config) (map[string]AccountState, error) {
+	client := utils.GetHTTPClient(time.Second*30, cfg.TlsSkipVerify)
+
+	accounts := make(map[string]AccountState)
+	fetched := 0
+	for page := 1; ; page++ {
+		brimstoneEndpoint := fmt.Sprintf("%s/v1/hashes?page=%d&page_size=%d", cfg.BrimstoneUrl, page, brimstone.MAX_HASH_PAGE_SIZE)
+		req, err := http.NewRequest(http.MethodGet, brimstoneEndpoint, nil)
+		if err != nil {
+			return nil, err
 		}
-		newhash := brimstone.Hash{
-			Hash: hmslhash,
-			Name: accounts[a].ID,
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", cfg.BrimstoneApiKey))
+
+		response, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, e := io.ReadAll(response.Body)
+		response.Body.Close()
+		if e != nil {
+			return nil, fmt.Errorf("failed to read response body: %s", e.Error())
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch the brimstone inventory: (%s) %s", response.Status, body)
 		}
 
-		req, ok := requests[accounts[a].SafeName]
-		if !ok {
-			req = brimstone.HashBatch{
-				Safename: accounts[a].SafeName,
-			}
+		var hashpage brimstone.StoredHashPage
+		if err := json.Unmarshal(body, &hashpage); err != nil {
+			return nil, fmt.Errorf("invalid brimstone inventory: %s", err.Error())
+		}
+		for i := 0; i < len(hashpage.Hashes); i++ {
+			accounts[hashpage.Hashes[i].Accountid] = AccountState{Safename: hashpage.Hashes[i].Safename}
+		}
+		fetched += len(hashpage.Hashes)
+		if len(hashpage.Hashes) == 0 || fetched >= hashpage.Total {
+			return accounts, nil
 		}
-		req.Hashes = append(req.Hashes, newhash)
-		requests[accounts[a].SafeName] = req
 	}
+}
 
-	// Send to brimstone
-	for k := range requests {
-		err = SendKeysToBrimstone(requests[k], cfg)
-		if err != nil {
-			log.Printf("Unable to send keys to brimstone: %s\n", err.Error())
-		}
+// DeleteAccountFromBrimstone - purge the hashes of an account deleted from PAM; an account
+// brimstone does not know is already purged
+func DeleteAccountFromBrimstone(safename string, accountid string, cfg config) error {
+	client := utils.GetHTTPClient(time.Second*30, cfg.TlsSkipVerify)
+
+	brimstoneEndpoint := fmt.Sprintf("%s/v1/safes/%s/accounts/%s", cfg.BrimstoneUrl, url.PathEscape(safename), url.PathEscape(accountid))
+	req, err := http.NewRequest(http.MethodDelete, brimstoneEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", cfg.BrimstoneApiKey))
+
+	response, err := client.Do(req)
+	if err != nil {
+		return err
 	}
+	defer response.Body.Close()
+
+	body, e := io.ReadAll(response.Body)
+	if e != nil {
+		return fmt.Errorf("failed to read response body: %s", e.Error())
+	}
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete from brimstone: (%s) %s", response.Status, body)
+	}
+	return nil
 }
 
 func SendKeysToBrimstone(keys brimstone.HashBatch, cfg config) error {
@@ -110,7 +245,7 @@ func SendKeysToBrimstone(keys brimstone.HashBatch, cfg config) error {
 	brimstoneEndpoint := fmt.Sprintf("%s/v1/hashes", cfg.BrimstoneUrl)
 	req, err := http.NewRequest(http.MethodPut, brimstoneEndpoint, bytes.NewReader(content))
 	if err != nil {
-		log.Fatalf("error in request to get platform token: %s", err.Error())
+		return fmt.Errorf("failed to create brimstone request: %s", err.Error())
 	}
 
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", cfg.BrimstoneApiKey))
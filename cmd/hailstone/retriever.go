@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// password sources, see HAILSTONE_PASSWORD_SOURCE
+const (
+	PASSWORD_SOURCE_PAM                = "pam"                // PAM REST Password/Retrieve as the PAM user
+	PASSWORD_SOURCE_CREDENTIALPROVIDER = "credentialprovider" // Credential Provider with the application id, -tags credentialprovider
+)
+
+// PasswordRetriever - retrieves the password of an account to hash
+type PasswordRetriever interface {
+	RetrievePassword(ctx context.Context, account pam.Account) (string, error)
+}
+
+// newRetriever - creates the retriever of a password source from the config
+type newRetriever func(cfg config, client *pam.Client) (PasswordRetriever, error)
+
+// passwordSources - the password sources built in; sources needing cgo register themselves
+// when hailstone is built with their tag
+var passwordSources = map[string]newRetriever{
+	PASSWORD_SOURCE_PAM: func(cfg config, client *pam.Client) (PasswordRetriever, error) {
+		return PAMRetriever{Client: client}, nil
+	},
+}
+
+// NewPasswordRetriever - the retriever of the password source
+func NewPasswordRetriever(source string, cfg config, client *pam.Client) (PasswordRetriever, error) {
+	create, ok := passwordSources[source]
+	if !ok {
+		if source == PASSWORD_SOURCE_CREDENTIALPROVIDER {
+			return nil, fmt.Errorf("hailstone built without the credential provider, rebuild with -tags credentialprovider")
+		}
+		var sources []string
+		for name := range passwordSources {
+			sources = append(sources, name)
+		}
+		sort.Strings(sources)
+		return nil, fmt.Errorf("unknown password source, %s, expected one of: %s", source, strings.Join(sources, ", "))
+	}
+	return create(cfg, client)
+}
+
+// PAMRetriever - retrieves passwords with the PAM REST api, as the PAM user
+type PAMRetriever struct {
+	Client *pam.Client
+}
+
+func (r PAMRetriever) RetrievePassword(ctx context.Context, account pam.Account) (string, error) {
+	return r.Client.FetchAccountPassword(ctx, account.ID)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPasswordRetriever(t *testing.T) {
+	client := newPAMAccountsStub(t, &[]pam.Account{})
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{source: PASSWORD_SOURCE_PAM},
+		{source: "vault", err: "unknown password source, vault, expected one of: "},
+		{source: "", err: "unknown password source, , expected one of: "},
+	}
+	for i := 0; i < len(tests); i++ {
+		retriever, err := NewPasswordRetriever(tests[i].source, config{}, client)
+		if tests[i].err != "" {
+			assert.ErrorContains(t, err, tests[i].err, tests[i].source)
+			assert.ErrorContains(t, err, PASSWORD_SOURCE_PAM, tests[i].source)
+			continue
+		}
+		assert.NoError(t, err, tests[i].source)
+		assert.Equal(t, PAMRetriever{Client: client}, retriever)
+	}
+
+	// the credential provider registers itself when built with -tags credentialprovider
+	if _, ok := passwordSources[PASSWORD_SOURCE_CREDENTIALPROVIDER]; !ok {
+		_, err := NewPasswordRetriever(PASSWORD_SOURCE_CREDENTIALPROVIDER, config{AppID: "Hailstone"}, client)
+		assert.ErrorContains(t, err, "rebuild with -tags credentialprovider")
+	}
+}
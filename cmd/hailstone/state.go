@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// SyncState - accounts hashed by the previous syncs, kept between runs so only the accounts
+// changed since the last sync have their password retrieved and hashed
+type SyncState struct {
+	LastSync time.Time               `json:"last_sync"`
+	Accounts map[string]AccountState `json:"accounts"` // by account id
+}
+
+// AccountState - account as of its last sync
+type AccountState struct {
+	Safename string `json:"safename"`
+	Modified int64  `json:"modified"`
+}
+
+// LoadState reads the state file; a missing file is an empty state, every account is synced
+func LoadState(path string) (SyncState, error) {
+	state := SyncState{Accounts: make(map[string]AccountState)}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return state, err
+	}
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]AccountState)
+	}
+	return state, nil
+}
+
+// Save writes the state file, replacing it only once fully written
+func (s SyncState) Save(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// accountModified - last change of the account password or properties
+func accountModified(account pam.Account) int64 {
+	modified := int64(account.SecretManagement.LastModifiedTime)
+	if account.CategoryModificationTime > modified {
+		modified = account.CategoryModificationTime
+	}
+	return modified
+}
+
+// Changed - the accounts new or modified since their last sync
+func (s SyncState) Changed(accounts []pam.Account) []pam.Account {
+	var changed []pam.Account
+	for i := 0; i < len(accounts); i++ {
+		synced, ok := s.Accounts[accounts[i].ID]
+		if !ok || synced.Safename != accounts[i].SafeName || synced.Modified != accountModified(accounts[i]) {
+			changed = append(changed, accounts[i])
+		}
+	}
+	return changed
+}
+
+// Deleted - the synced accounts no longer listed by PAM, by account id
+func (s SyncState) Deleted(accounts []pam.Account) map[string]AccountState {
+	listed := make(map[string]bool, len(accounts))
+	for i := 0; i < len(accounts); i++ {
+		listed[accounts[i].ID] = true
+	}
+	deleted := make(map[string]AccountState)
+	for id, synced := range s.Accounts {
+		if !listed[id] {
+			deleted[id] = synced
+		}
+	}
+	return deleted
+}
+
+// Synced - records the account as synced
+func (s SyncState) Synced(account pam.Account) {
+	s.Accounts[account.ID] = AccountState{Safename: account.SafeName, Modified: accountModified(account)}
+}
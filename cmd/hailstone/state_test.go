@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccount(id string, safename string, modified int) pam.Account {
+	return pam.Account{ID: id, Name: "account" + id, SafeName: safename, PlatformId: "UnixSSH", SecretManagement: pam.SecretManagement{LastModifiedTime: modified}}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// a missing state file is an empty state
+	state, err := LoadState(path)
+	assert.NoError(t, err)
+	assert.Empty(t, state.Accounts)
+
+	state.Synced(testAccount("1_1", "safe1", 100))
+	account := testAccount("1_2", "safe1", 100)
+	account.CategoryModificationTime = 200
+	state.Synced(account)
+	state.LastSync = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, state.Save(path))
+
+	loaded, err := LoadState(path)
+	assert.NoError(t, err)
+	assert.Equal(t, state.LastSync, loaded.LastSync)
+	assert.Equal(t, map[string]AccountState{
+		"1_1": {Safename: "safe1", Modified: 100},
+		"1_2": {Safename: "safe1", Modified: 200},
+	}, loaded.Accounts)
+
+	// no temp file is left next to the state file
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	assert.Len(t, files, 1)
+}
+
+func TestStateChangedAndDeleted(t *testing.T) {
+	state := SyncState{Accounts: make(map[string]AccountState)}
+	state.Synced(testAccount("1_1", "safe1", 100))
+	state.Synced(testAccount("1_2", "safe1", 100))
+	state.Synced(testAccount("1_3", "safe1", 100))
+	state.Synced(testAccount("1_4", "safe1", 100))
+
+	listed := []pam.Account{
+		testAccount("1_1", "safe1", 100), // unchanged
+		testAccount("1_2", "safe1", 300), // password changed
+		testAccount("1_3", "safe2", 100), // moved to another safe
+		testAccount("2_1", "safe2", 100), // new
+	}
+	changed := state.Changed(listed)
+	var ids []string
+	for i := 0; i < len(changed); i++ {
+		ids = append(ids, changed[i].ID)
+	}
+	assert.Equal(t, []string{"1_2", "1_3", "2_1"}, ids)
+
+	assert.Equal(t, map[string]AccountState{"1_4": {Safename: "safe1", Modified: 100}}, state.Deleted(listed))
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"golang.org/x/time/rate"
+)
+
+// HashResult - hmsl hash of the account password, or why the account was skipped
+type HashResult struct {
+	Account pam.Account
+	Hash    string
+	Err     error
+}
+
+// SyncFailure - account, or whole safe when AccountID is empty, that could not be synced
+type SyncFailure struct {
+	Safename  string
+	AccountID string
+	Err       error
+}
+
+// Syncer - syncs the hmsl hashes of the selected PAM accounts to brimstone; the state of the
+// synced accounts is kept between syncs, and in the state file, if any
+type Syncer struct {
+	Config      config
+	Client      *pam.Client       // lists the accounts
+	Retriever   PasswordRetriever // retrieves the passwords to hash
+	Filter      Filter
+	StateFile   string // incremental sync across runs when set
+	Full        bool   // hash every account on the next sync, even when unchanged
+	Concurrency int
+	RateLimit   float64 // password retrievals per second, 0 is unlimited
+	ExportDir   string  // signed hash exports are written here instead of sent to brimstone
+	ExportKey   []byte
+
+	state SyncState
+}
+
+// SafeSummary - counts of a sync for one safe
+type SafeSummary struct {
+	Safename string `json:"safename"`
+	Selected int    `json:"selected"`
+	Changed  int    `json:"changed"`
+	Synced   int    `json:"synced"`
+	Deleted  int    `json:"deleted"`
+	Failed   int    `json:"failed"`
+}
+
+// SyncSummary - counts of a sync, in total and by safe
+type SyncSummary struct {
+	Started  time.Time
+	Duration time.Duration
+	Listed   int
+	Selected int
+	Changed  int
+	Synced   int
+	Deleted  int
+	Safes    map[string]*SafeSummary
+	Failures []SyncFailure
+}
+
+func (s *SyncSummary) safe(safename string) *SafeSummary {
+	if s.Safes == nil {
+		s.Safes = make(map[string]*SafeSummary)
+	}
+	summary, ok := s.Safes[safename]
+	if !ok {
+		summary = &SafeSummary{Safename: safename}
+		s.Safes[safename] = summary
+	}
+	return summary
+}
+
+func (s *SyncSummary) fail(safename string, accountid string, err error) {
+	s.Failures = append(s.Failures, SyncFailure{Safename: safename, AccountID: accountid, Err: err})
+	s.safe(safename).Failed++
+}
+
+// LoadState - reads the state file, if any; without one, the first sync hashes every account
+func (s *Syncer) LoadState() error {
+	s.state = SyncState{Accounts: make(map[string]AccountState)}
+	if s.StateFile == "" {
+		return nil
+	}
+	state, err := LoadState(s.StateFile)
+	if err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// selectAccounts - the accounts selected by the filter, those to hash, and the excluded counts
+func (s *Syncer) selectAccounts(ctx context.Context) ([]pam.Account, []pam.Account, []pam.Account, map[string]int, error) {
+	listed, err := s.Client.FetchAccounts(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to fetch all safes: %w", err)
+	}
+	// filtered before any password is retrieved; accounts no longer selected are purged from
+	// brimstone, the same as deleted accounts
+	accounts, excluded := s.Filter.Select(listed)
+	tosync := accounts
+	if !s.Full {
+		tosync = s.state.Changed(accounts)
+	}
+	return listed, accounts, tosync, excluded, nil
+}
+
+// DryRun - prints the selected accounts without retrieving any password
+func (s *Syncer) DryRun(ctx context.Context, w io.Writer) error {
+	_, accounts, tosync, excluded, err := s.selectAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	PrintSelection(w, s.Filter, accounts, tosync, excluded)
+	return nil
+}
+
+// Sync hashes the selected accounts changed since the last sync, sends the hashes to
+// brimstone, and purges the accounts no longer selected. Failed accounts are in the summary,
+// and synced again next time; the error is set when the sync could not run at all.
+func (s *Syncer) Sync(ctx context.Context) (SyncSummary, error) {
+	summary := SyncSummary{Started: time.Now()}
+	listed, accounts, tosync, _, err := s.selectAccounts(ctx)
+	if err != nil {
+		return summary, err
+	}
+	summary.Listed = len(listed)
+	summary.Selected = len(accounts)
+	summary.Changed = len(tosync)
+	for i := 0; i < len(accounts); i++ {
+		summary.safe(accounts[i].SafeName).Selected++
+	}
+	log.Printf("INFO: %d account(s), %d selected, %d changed since the last sync\n", len(listed), len(accounts), len(tosync))
+
+	results := HashAccounts(ctx, s.Retriever, tosync, s.Concurrency, s.RateLimit)
+	requests := make(map[string]brimstone.HashBatch)
+	for a := range results {
+		account := results[a].Account
+		summary.safe(account.SafeName).Changed++
+		if results[a].Err != nil {
+			log.Printf("ERROR: skipping account id, %s, safename: %s: %s\n", account.ID, account.SafeName, results[a].Err.Error())
+			summary.fail(account.SafeName, account.ID, results[a].Err)
+			continue
+		}
+		newhash := brimstone.Hash{
+			Hash: results[a].Hash,
+			Name: account.ID,
+		}
+
+		req, ok := requests[account.SafeName]
+		if !ok {
+			req = brimstone.HashBatch{
+				Safename: account.SafeName,
+			}
+		}
+		req.Hashes = append(req.Hashes, newhash)
+		requests[account.SafeName] = req
+	}
+
+	// Send to brimstone, or export; an account is only recorded as synced once brimstone, or
+	// the export, has its hash
+	sent := s.send(requests, &summary)
+	for k := range sent {
+		for i := 0; i < len(results); i++ {
+			if results[i].Err == nil && results[i].Account.SafeName == k {
+				s.state.Synced(results[i].Account)
+				summary.Synced++
+				summary.safe(k).Synced++
+			}
+		}
+	}
+
+	// accounts synced before, but no longer in PAM, are purged from brimstone; an export only
+	// carries hashes, they are purged once hailstone syncs with brimstone directly
+	deleted := s.state.Deleted(accounts)
+	if s.ExportDir == "" && s.state.LastSync.IsZero() {
+		deleted, err = s.inventoryDeleted(accounts)
+		if err != nil {
+			log.Printf("ERROR: deleted accounts not purged, unable to fetch the brimstone inventory: %s\n", err.Error())
+			deleted = nil
+		}
+	}
+	if s.ExportDir != "" && len(deleted) > 0 {
+		log.Printf("INFO: %d deleted account(s) not purged from brimstone in export mode\n", len(deleted))
+		deleted = nil
+	}
+	for id, synced := range deleted {
+		err = DeleteAccountFromBrimstone(synced.Safename, id, s.Config)
+		if err != nil {
+			log.Printf("Unable to delete account, %s, from brimstone: %s\n", id, err.Error())
+			summary.fail(synced.Safename, id, err)
+			continue
+		}
+		log.Printf("INFO: deleted account, %s, safename: %s, no longer in PAM\n", id, synced.Safename)
+		delete(s.state.Accounts, id)
+		summary.Deleted++
+		summary.safe(synced.Safename).Deleted++
+	}
+
+	s.Full = false
+	s.state.LastSync = time.Now()
+	if s.StateFile != "" {
+		if err := s.state.Save(s.StateFile); err != nil {
+			return summary, fmt.Errorf("failed to write state file, %s: %w", s.StateFile, err)
+		}
+	}
+
+	summary.Duration = time.Since(summary.Started)
+	log.Printf("INFO: sync summary, accounts: %d, changed: %d, synced: %d, deleted: %d, failures: %d\n", summary.Selected, summary.Changed, summary.Synced, summary.Deleted, len(summary.Failures))
+	return summary, nil
+}
+
+// inventoryDeleted - without a previous sync, no state file or a first run, the synced accounts
+// are those of the brimstone inventory in the safes selected by the filter; the accounts of other
+// safes may be synced by another hailstone and are left alone
+func (s *Syncer) inventoryDeleted(accounts []pam.Account) (map[string]AccountState, error) {
+	inventory, err := FetchBrimstoneAccounts(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]string, len(accounts))
+	for i := 0; i < len(accounts); i++ {
+		selected[accounts[i].ID] = accounts[i].SafeName
+	}
+	deleted := make(map[string]AccountState)
+	for id, synced := range inventory {
+		if safename, ok := selected[id]; ok && safename == synced.Safename {
+			continue
+		}
+		if s.Filter.SelectsSafe(synced.Safename) {
+			deleted[id] = synced
+		}
+	}
+	return deleted, nil
+}
+
+// send - sends the hash batches to brimstone, or writes them to a signed export; returns the
+// safes sent
+func (s *Syncer) send(requests map[string]brimstone.HashBatch, summary *SyncSummary) map[string]bool {
+	sent := make(map[string]bool)
+	if s.ExportDir == "" {
+		for k := range requests {
+			err := SendKeysToBrimstone(requests[k], s.Config)
+			if err != nil {
+				log.Printf("Unable to send keys to brimstone: %s\n", err.Error())
+				summary.fail(k, "", err)
+				continue
+			}
+			sent[k] = true
+		}
+		return sent
+	}
+
+	if len(requests) == 0 {
+		return sent
+	}
+	var batches []brimstone.HashBatch
+	for k := range requests {
+		batches = append(batches, requests[k])
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Safename < batches[j].Safename })
+	path, err := WriteExport(s.ExportDir, batches, s.ExportKey, time.Now())
+	if err != nil {
+		log.Printf("Unable to write hash export: %s\n", err.Error())
+		for k := range requests {
+			summary.fail(k, "", err)
+		}
+		return sent
+	}
+	log.Printf("INFO: wrote hash export, %s, safes: %d\n", path, len(batches))
+	for k := range requests {
+		sent[k] = true
+	}
+	return sent
+}
+
+// HashAccounts retrieves the passwords of the accounts and computes their hmsl hashes with
+// concurrency workers; password retrievals are limited to rps per second, 0 is unlimited.
+// Results are in the order of the accounts.
+func HashAccounts(ctx context.Context, retriever PasswordRetriever, accounts []pam.Account, concurrency int, rps float64) []HashResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if rps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(rps), 1)
+	}
+
+	results := make([]HashResult, len(accounts))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = hashAccount(ctx, retriever, limiter, accounts[i])
+			}
+		}()
+	}
+	for i := 0; i < len(accounts); i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func hashAccount(ctx context.Context, retriever PasswordRetriever, limiter *rate.Limiter, account pam.Account) HashResult {
+	result := HashResult{Account: account}
+	if err := limiter.Wait(ctx); err != nil {
+		result.Err = err
+		return result
+	}
+	password, err := retriever.RetrievePassword(ctx, account)
+	if err != nil {
+		result.Err = fmt.Errorf("unable to retrieve password: %w", err)
+		return result
+	}
+	hmslhash, err := hmsl.ComputeHash(password)
+	if err != nil {
+		result.Err = fmt.Errorf("unable to compute hmsl hash: %w", err)
+		return result
+	}
+	result.Hash = hmslhash
+	return result
+}
+
+// printFailures - logs every account and safe not synced, then the safes not fully synced
+func printFailures(failures []SyncFailure) {
+	safes := make(map[string]bool)
+	for i := 0; i < len(failures); i++ {
+		safes[failures[i].Safename] = true
+		if failures[i].AccountID == "" {
+			log.Printf("ERROR: not synced, safename: %s: %s\n", failures[i].Safename, failures[i].Err.Error())
+			continue
+		}
+		log.Printf("ERROR: not synced, safename: %s, account id: %s: %s\n", failures[i].Safename, failures[i].AccountID, failures[i].Err.Error())
+	}
+	safenames := make([]string, 0, len(safes))
+	for safename := range safes {
+		safenames = append(safenames, safename)
+	}
+	sort.Strings(safenames)
+	log.Printf("ERROR: %d safe(s) not fully synced: %s\n", len(safenames), strings.Join(safenames, ", "))
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/brimstone"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+// brimstoneStub - records the hash batches sent and the accounts purged, and lists the
+// accounts of the inventory
+type brimstoneStub struct {
+	mu        sync.Mutex
+	batches   []brimstone.HashBatch
+	deleted   []string
+	inventory []brimstone.StoredHash
+}
+
+func newBrimstoneStub(t *testing.T) (*brimstoneStub, string) {
+	stub := &brimstoneStub{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer apikey", r.Header.Get("Authorization"))
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			var batch brimstone.HashBatch
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+			stub.batches = append(stub.batches, batch)
+		case http.MethodDelete:
+			stub.deleted = append(stub.deleted, r.URL.Path)
+		case http.MethodGet:
+			assert.Equal(t, "/v1/hashes", r.URL.Path)
+			json.NewEncoder(w).Encode(brimstone.StoredHashPage{Page: 1, PageSize: brimstone.MAX_HASH_PAGE_SIZE, Total: len(stub.inventory), Hashes: stub.inventory})
+			return
+		}
+		w.Write([]byte(`"ok"`))
+	}))
+	t.Cleanup(server.Close)
+	return stub, server.URL
+}
+
+// newPAMAccountsStub - lists the accounts, the test changes them between syncs
+func newPAMAccountsStub(t *testing.T, accounts *[]pam.Account) *pam.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/PasswordVault/API/Accounts", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pam.FetchAccountsResponse{Value: *accounts, Count: len(*accounts)})
+	}))
+	t.Cleanup(server.Close)
+
+	config := pam.NewConfig("", server.URL, "safe1", "DummyPlatform", "", "", false)
+	client := pam.NewClient(server.URL, config)
+	client.Session = pam.NewSession("tok", "Bearer", time.Now().Add(time.Hour))
+	return &client
+}
+
+// passwordsRetriever - retrieves the passwords by account id
+type passwordsRetriever struct {
+	mu        sync.Mutex
+	passwords map[string]string
+	retrieved []string
+}
+
+func (r *passwordsRetriever) RetrievePassword(ctx context.Context, account pam.Account) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retrieved = append(r.retrieved, account.ID)
+	password, ok := r.passwords[account.ID]
+	if !ok {
+		return "", errors.New("no password")
+	}
+	return password, nil
+}
+
+func TestSyncIncrementalAndPurge(t *testing.T) {
+	stub, brimstoneurl := newBrimstoneStub(t)
+	accounts := []pam.Account{
+		testAccount("1_1", "safe1", 100),
+		testAccount("2_1", "safe2", 100),
+		testAccount("2_2", "safe2", 100),
+	}
+	retriever := &passwordsRetriever{passwords: map[string]string{"1_1": "p11", "2_1": "p21", "2_2": "p22"}}
+	statefile := filepath.Join(t.TempDir(), "state.json")
+	syncer := &Syncer{
+		Config:      config{BrimstoneUrl: brimstoneurl, BrimstoneApiKey: "apikey"},
+		Client:      newPAMAccountsStub(t, &accounts),
+		Retriever:   retriever,
+		StateFile:   statefile,
+		Concurrency: 2,
+	}
+	assert.NoError(t, syncer.LoadState())
+
+	ctx := context.Background()
+	summary, err := syncer.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, summary.Synced)
+	assert.Len(t, stub.batches, 2)
+
+	// nothing changed, no password is retrieved
+	retriever.retrieved = nil
+	summary, err = syncer.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.Changed)
+	assert.Empty(t, retriever.retrieved)
+
+	// an account removed from PAM, and a safe no longer selected by the filter, are purged
+	accounts = accounts[:2]
+	syncer.Filter, err = NewFilter("", "safe2", "", "", "")
+	assert.NoError(t, err)
+	summary, err = syncer.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Deleted)
+	assert.Equal(t, 2, summary.Safes["safe2"].Deleted)
+	assert.ElementsMatch(t, []string{"/v1/safes/safe2/accounts/2_1", "/v1/safes/safe2/accounts/2_2"}, stub.deleted)
+	assert.Empty(t, retriever.retrieved)
+
+	// the state file only keeps the selected accounts
+	state, err := LoadState(statefile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]AccountState{"1_1": {Safename: "safe1", Modified: 100}}, state.Accounts)
+
+	// a restarted hailstone reads the state file and only hashes the changed account
+	accounts[0].SecretManagement.LastModifiedTime = 200
+	restarted := *syncer
+	assert.NoError(t, restarted.LoadState())
+	summary, err = restarted.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Synced)
+	assert.Equal(t, []string{"1_1"}, retriever.retrieved)
+}
+
+func TestSyncFailedAccountRetried(t *testing.T) {
+	_, brimstoneurl := newBrimstoneStub(t)
+	accounts := []pam.Account{testAccount("1_1", "safe1", 100), testAccount("1_2", "safe1", 100)}
+	retriever := &passwordsRetriever{passwords: map[string]string{"1_1": "p11"}}
+	syncer := &Syncer{
+		Config:    config{BrimstoneUrl: brimstoneurl, BrimstoneApiKey: "apikey"},
+		Client:    newPAMAccountsStub(t, &accounts),
+		Retriever: retriever,
+	}
+	assert.NoError(t, syncer.LoadState())
+
+	summary, err := syncer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Synced)
+	assert.Len(t, summary.Failures, 1)
+	assert.Equal(t, "1_2", summary.Failures[0].AccountID)
+
+	// the failed account is not recorded as synced, the next sync retries it
+	retriever.retrieved = nil
+	_, err = syncer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1_2"}, retriever.retrieved)
+}
+
+func TestSyncWithoutStateFilePurgesFromInventory(t *testing.T) {
+	stub, brimstoneurl := newBrimstoneStub(t)
+	stub.inventory = []brimstone.StoredHash{
+		{Safename: "safe1", Accountid: "1_1"},
+		{Safename: "safe1", Accountid: "1_1"}, // an older version
+		{Safename: "safe1", Accountid: "1_2"}, // deleted from PAM
+		{Safename: "safe1", Accountid: "2_2"}, // moved to safe2
+		{Safename: "other", Accountid: "9_1"}, // safe not selected, synced by another hailstone
+	}
+	accounts := []pam.Account{testAccount("1_1", "safe1", 100), testAccount("2_2", "safe2", 100)}
+	filter, err := NewFilter("safe*", "", "", "", "")
+	assert.NoError(t, err)
+	syncer := &Syncer{
+		Config:    config{BrimstoneUrl: brimstoneurl, BrimstoneApiKey: "apikey"},
+		Client:    newPAMAccountsStub(t, &accounts),
+		Retriever: &passwordsRetriever{passwords: map[string]string{"1_1": "p11", "2_2": "p22"}},
+		Filter:    filter,
+	}
+	assert.NoError(t, syncer.LoadState())
+
+	// a one-shot run without a state file still purges the deleted accounts
+	summary, err := syncer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Synced)
+	assert.Equal(t, 2, summary.Deleted)
+	assert.ElementsMatch(t, []string{"/v1/safes/safe1/accounts/1_2", "/v1/safes/safe1/accounts/2_2"}, stub.deleted)
+
+	// the next cycle of a daemon uses the state of the previous one
+	stub.deleted = nil
+	stub.inventory = append(stub.inventory, brimstone.StoredHash{Safename: "safe1", Accountid: "1_3"})
+	_, err = syncer.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, stub.deleted)
+}
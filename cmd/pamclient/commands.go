@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// COMMANDS_USAGE - pamclient subcommands; without a subcommand pamclient runs the add and change password cycle
+const COMMANDS_USAGE = `
+Commands:
+  accounts                                 list the accounts, filtered by -safename, -search and -searchtype
+  get <accountid>                          get the account
+  delete <accountid>                       delete the account
+  verify <accountid>                       mark the account for verification by the CPM
+  reconcile <accountid>                    mark the account for reconciliation by the CPM
+  set-next-password <accountid> <password> set the next password, changed now with -change-immediately
+  update <accountid> <path> <value>        replace the account property, ex: update 12_1 /address db.example.com
+  enable-auto <accountid>                  enable the automatic management of the account
+  disable-auto <accountid> [reason]        disable the automatic management of the account
+  safes                                    list the safes
+  safe-members <safename>                  list the members of the safe
+  activities <accountid>                   list the activities of the account
+`
+
+// commandOptions - flags used by the subcommands
+type commandOptions struct {
+	Accounts          pam.FetchAccountsOptions
+	ChangeImmediately bool
+}
+
+// RunCommand runs the pamclient subcommand, printing its result as json
+func RunCommand(ctx context.Context, client *pam.Client, args []string, opts commandOptions) error {
+	command := args[0]
+	params := args[1:]
+	need := func(n int, usage string) error {
+		if len(params) < n {
+			return fmt.Errorf("usage: pamclient %s %s", command, usage)
+		}
+		return nil
+	}
+
+	switch command {
+	case "accounts":
+		accounts, err := client.FetchAccountsWithOptions(ctx, opts.Accounts)
+		if err != nil {
+			return err
+		}
+		return printJSON(accounts)
+	case "get":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		account, err := client.GetAccount(ctx, params[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(account)
+	case "delete":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		return client.DeleteAccount(ctx, params[0])
+	case "verify":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		return client.VerifyCredentials(ctx, params[0])
+	case "reconcile":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		return client.ReconcileCredentials(ctx, params[0])
+	case "set-next-password":
+		if err := need(2, "<accountid> <password>"); err != nil {
+			return err
+		}
+		return client.SetNextPassword(ctx, params[0], params[1], opts.ChangeImmediately)
+	case "update":
+		if err := need(3, "<accountid> <path> <value>"); err != nil {
+			return err
+		}
+		// json values, ex: true or 3, are sent as is, anything else as a string
+		var value any
+		if json.Unmarshal([]byte(params[2]), &value) != nil {
+			value = params[2]
+		}
+		account, err := client.UpdateAccount(ctx, params[0], []pam.PatchOperation{{Op: "replace", Path: params[1], Value: value}})
+		if err != nil {
+			return err
+		}
+		return printJSON(account)
+	case "enable-auto", "disable-auto":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		reason := ""
+		if len(params) > 1 {
+			reason = params[1]
+		}
+		account, err := client.SetAutomaticManagement(ctx, params[0], command == "enable-auto", reason)
+		if err != nil {
+			return err
+		}
+		return printJSON(account)
+	case "safes":
+		safes, err := client.FetchSafes(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(safes)
+	case "safe-members":
+		if err := need(1, "<safename>"); err != nil {
+			return err
+		}
+		members, err := client.FetchSafeMembers(ctx, params[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(members)
+	case "activities":
+		if err := need(1, "<accountid>"); err != nil {
+			return err
+		}
+		activities, err := client.FetchAccountActivities(ctx, params[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(activities)
+	}
+	return fmt.Errorf("unknown command, %s%s", command, COMMANDS_USAGE)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
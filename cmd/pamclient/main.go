@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -23,12 +24,21 @@ func main() {
 	pamuser := flag.String("pamuser", "", "PAM config PAM User")
 	pampass := flag.String("pampass", "", "PAM config PAM Pass")
 	safename := flag.String("safename", "", "PAM config PAM Safe Name")
+	search := flag.String("search", "", "Only list the accounts matching the search keywords")
+	searchtype := flag.String("searchtype", "", "Search type, contains (default) or startswith")
+
+	changeimmediately := flag.Bool("change-immediately", false, "set-next-password changes the password now instead of at the next CPM change")
 
 	tlsskipverify := flag.Bool("tls-skip-verify", false, "Skip TLS Verify when calling pam (for self-signed cert)")
 
 	listAccountsFlag := flag.Bool("l", false, "List Accounts and exit")
 	debug := flag.Bool("d", false, "Enable debug settings")
 	ver := flag.Bool("version", false, "Print version")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: pamclient [flags] [command]\n")
+		flag.PrintDefaults()
+		fmt.Fprint(flag.CommandLine.Output(), COMMANDS_USAGE)
+	}
 	flag.Parse()
 
 	if *ver {
@@ -42,22 +52,37 @@ func main() {
 
 	pamconfig := pam.NewConfig(*idtenanturl, *pcloudurl, *safename, "DummyPlatform", *pamuser, *pampass, TLS_SKIP_VERIFY)
 	client := pam.NewClient(pamconfig.PCloudURL, pamconfig)
-	err := client.RefreshSessionToken()
+	ctx := context.Background()
+	err := client.RefreshSessionToken(ctx)
 	if err != nil {
 		log.Fatalf("failed to fetch session token: %s", err.Error())
 	}
 
-	allaccounts, err := client.FetchAccounts()
-	var accounts []pam.Account
-
-	// filter out everything except the safename we're interested in
-	for i := 0; i < len(allaccounts); i++ {
-		if allaccounts[i].SafeName == *safename {
-			accounts = append(accounts, allaccounts[i])
+	accountsopts := pam.FetchAccountsOptions{
+		SafeName:   *safename,
+		Search:     *search,
+		SearchType: *searchtype,
+	}
+	if flag.NArg() > 0 {
+		err = RunCommand(ctx, &client, flag.Args(), commandOptions{Accounts: accountsopts, ChangeImmediately: *changeimmediately})
+		if err != nil {
+			log.Fatalf("%s: %s", flag.Arg(0), err.Error())
 		}
+		os.Exit(0)
+	}
+
+	// the accounts are filtered by the vault and fetched page by page
+	var accounts []pam.Account
+	it := client.Accounts(ctx, accountsopts)
+	for it.Next() {
 		if listAccounts {
-			log.Printf("ACCT: %+v\n", allaccounts[i])
+			log.Printf("ACCT: %+v\n", it.Account())
+			continue
 		}
+		accounts = append(accounts, it.Account())
+	}
+	if it.Err() != nil {
+		log.Fatalf("failed to fetch accounts: %s", it.Err().Error())
 	}
 	if listAccounts {
 		os.Exit(0)
@@ -67,12 +92,12 @@ func main() {
 	if len(accounts) > 0 {
 		accountid = accounts[0].ID
 	}
-	curpassword, err := client.FetchAccountPassword(accountid)
+	curpassword, err := client.FetchAccountPassword(ctx, accountid)
 	if err != nil {
 		log.Printf("ERROR: failed to fetch password for account id, %s: %s\n", accountid, err.Error())
 	}
-	ActionChangeAccountPassword(&client, accountid)
-	newpassword, err := client.FetchAccountPassword(accountid)
+	ActionChangeAccountPassword(ctx, &client, accountid)
+	newpassword, err := client.FetchAccountPassword(ctx, accountid)
 	if err != nil {
 		log.Printf("ERROR: failed to fetch password after change for account id, %s: %s\n", accountid, err.Error())
 	}
@@ -87,17 +112,17 @@ func main() {
 	newacctname := fmt.Sprintf("pamclient-%s", newseq)
 	newacctsecret := fmt.Sprintf("myleakedsecret-%s", newseq)
 
-	newacct, err := ActionAddAccount(&client, newacctname, *safename, newacctsecret, "password", "https://dashboard.gitguardian.com/workspace/000000/incident/00000000")
+	newacct, err := ActionAddAccount(ctx, &client, newacctname, *safename, newacctsecret, "password", "https://dashboard.gitguardian.com/workspace/000000/incident/00000000")
 	newaccountid := newacct.ID
 	log.Printf("New account id: %s\n", newaccountid)
 
 	// Try the cycle with the new account id
-	curpassword, err = client.FetchAccountPassword(newaccountid)
+	curpassword, err = client.FetchAccountPassword(ctx, newaccountid)
 	if err != nil {
 		log.Printf("ERROR: failed to fetch password for account id, %s: %s\n", newaccountid, err.Error())
 	}
-	ActionChangeAccountPassword(&client, newaccountid)
-	newpassword, err = client.FetchAccountPassword(newaccountid)
+	ActionChangeAccountPassword(ctx, &client, newaccountid)
+	newpassword, err = client.FetchAccountPassword(ctx, newaccountid)
 	if err != nil {
 		log.Printf("ERROR: failed to fetch password after change for account id, %s: %s\n", newaccountid, err.Error())
 	}
@@ -105,18 +130,18 @@ func main() {
 		log.Printf("INFO: passwords are the same: %s=%s\n", curpassword, newpassword)
 	}
 
-	ActionFindAccountIdFromName(&client, *safename, newacctname)
+	ActionFindAccountIdFromName(ctx, &client, *safename, newacctname)
 }
 
-func ActionChangeAccountPassword(client *pam.Client, accountid string) {
-	code, err := client.ChangePasswordImmediately(accountid)
+func ActionChangeAccountPassword(ctx context.Context, client *pam.Client, accountid string) {
+	code, err := client.ChangePasswordImmediately(ctx, accountid)
 	if err != nil {
 		log.Printf("ERROR: (status code:%d) failed to change password for acct id, %s: %s\n", code, accountid, err.Error())
 	}
 
 }
 
-func ActionAddAccount(client *pam.Client, acctname string, safename string, secret string, secrettype string, ggincidenturl string) (pam.PostAddAccountResponse, error) {
+func ActionAddAccount(ctx context.Context, client *pam.Client, acctname string, safename string, secret string, secrettype string, ggincidenturl string) (pam.PostAddAccountResponse, error) {
 	list := "abcdefghijklmnopqrstuvwxyz01234567890"
 	seq := []rune(list)
 	newseq := utils.RandSeq(seq, 6)
@@ -132,21 +157,21 @@ func ActionAddAccount(client *pam.Client, acctname string, safename string, secr
 		SecretType:                secrettype,
 		PlatformAccountProperties: pam.PlatformAccountProperties{},
 	}
-	newaccount, _, err := client.AddAccount(addreq)
+	newaccount, _, err := client.AddAccount(ctx, addreq)
 	if err != nil {
 		return pam.PostAddAccountResponse{}, err
 	}
 	return newaccount, nil
 }
 
-func ActionFindAccountIdFromName(client *pam.Client, safename string, accountname string) {
-	err := client.RefreshSessionToken()
+func ActionFindAccountIdFromName(ctx context.Context, client *pam.Client, safename string, accountname string) {
+	err := client.RefreshSessionToken(ctx)
 	if err != nil {
 		log.Printf("Error: %s\n", err.Error())
 		return
 	}
 
-	acctid, rescode, err := client.FetchAccountIdFromAccountName(safename, accountname)
+	acctid, rescode, err := client.FetchAccountIdFromAccountName(ctx, safename, accountname)
 	if rescode > 299 {
 		log.Printf("Result Code=%d\n", rescode)
 	}
@@ -0,0 +1,193 @@
+package brimstone
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// What triggered a remediation
+const (
+	AUDIT_SOURCE_GG_WEBHOOK   = "gg_webhook"
+	AUDIT_SOURCE_GG_RECONCILE = "gg_reconcile"
+	AUDIT_SOURCE_HMSL_SCAN    = "hmsl_scan"
+	AUDIT_SOURCE_CPM          = "cpm"
+)
+
+// What brimstone did about it
+const (
+	AUDIT_ACTION_ROTATE      = "rotate"
+	AUDIT_ACTION_ADD_ACCOUNT = "add_account"
+	AUDIT_ACTION_UPDATE_HASH = "update_hash"
+	AUDIT_ACTION_SKIP        = "skip"
+)
+
+// default number of events returned by the audit endpoint
+const DEFAULT_AUDIT_LIMIT = 1000
+
+// Audit record of every remediation brimstone performed (or skipped) and why
+type RemediationEvent struct {
+	gorm.Model
+	Source         string `gorm:"index"`
+	IncidentID     int
+	IncidentURL    string
+	HashPrefix     string // only the hmsl hash prefix is kept, never the full hash
+	Safename       string `gorm:"index"`
+	AccountID      string `gorm:"index"`
+	Action         string
+	ResponseCode   int
+	Error          string
+	RotationStatus string // rotate events of tracked rotations: pending, succeeded or escalated
+}
+
+// RecordRemediation saves the remediation event; failing to save is logged but does not
+// fail the remediation itself. Dry runs do not remediate, so, nothing is recorded. Returns
+// the saved event
+func (b Brimstone) RecordRemediation(event RemediationEvent) RemediationEvent {
+	db := b.Db
+
+	if b.DryRun {
+		return event
+	}
+
+	log.Printf("AUDIT: source=%s, action=%s, safename=%s, account id=%s, hash prefix=%s, incident=%d, code=%d, error=%s\n",
+		event.Source, event.Action, event.Safename, event.AccountID, event.HashPrefix, event.IncidentID, event.ResponseCode, event.Error)
+
+	result := db.Create(&event)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to save remediation event: %s\n", result.Error.Error())
+	}
+	return event
+}
+
+// HashPrefix - hmsl hash prefix as sent to HMSL in prefix mode
+func HashPrefix(hmslhash string) string {
+	return utils.FirstN(hmslhash, 5)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func remediationEventToAPI(e RemediationEvent) AuditEvent {
+	rsp := AuditEvent{
+		Id:           int(e.ID),
+		CreatedAt:    e.CreatedAt,
+		Source:       e.Source,
+		Action:       e.Action,
+		Safename:     e.Safename,
+		AccountId:    e.AccountID,
+		ResponseCode: &e.ResponseCode,
+	}
+	if e.IncidentID != 0 {
+		rsp.IncidentId = &e.IncidentID
+	}
+	if e.IncidentURL != "" {
+		rsp.IncidentUrl = &e.IncidentURL
+	}
+	if e.HashPrefix != "" {
+		rsp.HashPrefix = &e.HashPrefix
+	}
+	if e.Error != "" {
+		rsp.Error = &e.Error
+	}
+	if e.RotationStatus != "" {
+		rsp.RotationStatus = &e.RotationStatus
+	}
+	return rsp
+}
+
+// AuditGet - GET /v1/audit
+func (b Brimstone) AuditGet(ctx echo.Context, params AuditGetParams) error {
+	db := b.Db
+
+	limit := DEFAULT_AUDIT_LIMIT
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	query := db.Order("created_at desc").Limit(limit)
+	if params.Safename != nil {
+		query = query.Where("safename = ?", *params.Safename)
+	}
+	if params.Accountid != nil {
+		query = query.Where("account_id = ?", *params.Accountid)
+	}
+	if params.Source != nil {
+		query = query.Where("source = ?", string(*params.Source))
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at < ?", *params.To)
+	}
+
+	var events []RemediationEvent
+	result := query.Find(&events)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch remediation events")
+	}
+
+	if params.Format != nil && *params.Format == Csv {
+		return sendAuditCSV(ctx, events)
+	}
+
+	rsp := []AuditEvent{}
+	for i := 0; i < len(events); i++ {
+		rsp = append(rsp, remediationEventToAPI(events[i]))
+	}
+	if params.Format != nil && *params.Format == Json {
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=brimstone-audit.json")
+	}
+	return ctx.JSON(200, rsp)
+}
+
+// sendAuditCSV writes the remediation events as a csv attachment
+func sendAuditCSV(ctx echo.Context, events []RemediationEvent) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, "text/csv")
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=brimstone-audit.csv")
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Response())
+	err := w.Write([]string{"id", "created_at", "source", "action", "safename", "account_id", "incident_id", "incident_url", "hash_prefix", "response_code", "error", "rotation_status"})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(events); i++ {
+		e := events[i]
+		incidentid := ""
+		if e.IncidentID != 0 {
+			incidentid = strconv.Itoa(e.IncidentID)
+		}
+		err = w.Write([]string{
+			fmt.Sprintf("%d", e.ID),
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			e.Source,
+			e.Action,
+			e.Safename,
+			e.AccountID,
+			incidentid,
+			e.IncidentURL,
+			e.HashPrefix,
+			strconv.Itoa(e.ResponseCode),
+			e.Error,
+			e.RotationStatus,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
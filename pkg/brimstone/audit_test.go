@@ -0,0 +1,54 @@
+package brimstone
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditGetFilters(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.RecordRemediation(RemediationEvent{Source: AUDIT_SOURCE_GG_WEBHOOK, Safename: "safe1", AccountID: "1_1", Action: AUDIT_ACTION_ROTATE, ResponseCode: 200})
+	b.RecordRemediation(RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Safename: "safe1", AccountID: "1_2", Action: AUDIT_ACTION_ROTATE, ResponseCode: 500, Error: "boom"})
+	b.RecordRemediation(RemediationEvent{Source: AUDIT_SOURCE_CPM, Safename: "safe2", AccountID: "2_1", Action: AUDIT_ACTION_UPDATE_HASH})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/audit", nil)
+	rec := httptest.NewRecorder()
+	safename := "safe1"
+	source := AuditGetParamsSource(AUDIT_SOURCE_HMSL_SCAN)
+	err := b.AuditGet(e.NewContext(req, rec), AuditGetParams{Safename: &safename, Source: &source})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var events []AuditEvent
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	assert.Len(t, events, 1)
+	assert.Equal(t, "1_2", events[0].AccountId)
+	assert.Equal(t, "boom", *events[0].Error)
+}
+
+func TestAuditGetCSV(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.RecordRemediation(RemediationEvent{Source: AUDIT_SOURCE_GG_WEBHOOK, IncidentID: 42, Safename: "safe1", AccountID: "1_1", Action: AUDIT_ACTION_ROTATE, ResponseCode: 200})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/audit?format=csv", nil)
+	rec := httptest.NewRecorder()
+	format := Csv
+	err := b.AuditGet(e.NewContext(req, rec), AuditGetParams{Format: &format})
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+
+	records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "42", records[1][6])
+	assert.Equal(t, "1_1", records[1][5])
+}
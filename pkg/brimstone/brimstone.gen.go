@@ -12,14 +12,80 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
 )
 
 const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for ScanJobRequestMode.
+const (
+	Full   ScanJobRequestMode = "full"
+	Prefix ScanJobRequestMode = "prefix"
+)
+
+// Defines values for WriteBackPolicyMode.
+const (
+	None    WriteBackPolicyMode = "none"
+	Note    WriteBackPolicyMode = "note"
+	Resolve WriteBackPolicyMode = "resolve"
+)
+
+// Defines values for AuditGetParamsSource.
+const (
+	Cpm         AuditGetParamsSource = "cpm"
+	GgReconcile AuditGetParamsSource = "gg_reconcile"
+	GgWebhook   AuditGetParamsSource = "gg_webhook"
+	HmslScan    AuditGetParamsSource = "hmsl_scan"
+)
+
+// Defines values for AuditGetParamsFormat.
+const (
+	Csv  AuditGetParamsFormat = "csv"
+	Json AuditGetParamsFormat = "json"
+)
+
+// Defines values for IncidentsReconcilePostParamsStatus.
+const (
+	ASSIGNED  IncidentsReconcilePostParamsStatus = "ASSIGNED"
+	IGNORED   IncidentsReconcilePostParamsStatus = "IGNORED"
+	RESOLVED  IncidentsReconcilePostParamsStatus = "RESOLVED"
+	TRIGGERED IncidentsReconcilePostParamsStatus = "TRIGGERED"
+)
+
+// Defines values for RotationsGetParamsStatus.
+const (
+	Escalated RotationsGetParamsStatus = "escalated"
+	Pending   RotationsGetParamsStatus = "pending"
+	Succeeded RotationsGetParamsStatus = "succeeded"
+)
+
+// AuditEvent defines model for AuditEvent.
+type AuditEvent struct {
+	AccountId string `json:"account_id"`
+
+	// Action rotate, add_account, update_hash or skip
+	Action       string    `json:"action"`
+	CreatedAt    time.Time `json:"created_at"`
+	Error        *string   `json:"error,omitempty"`
+	HashPrefix   *string   `json:"hash_prefix,omitempty"`
+	Id           int       `json:"id"`
+	IncidentId   *int      `json:"incident_id,omitempty"`
+	IncidentUrl  *string   `json:"incident_url,omitempty"`
+	ResponseCode *int      `json:"response_code,omitempty"`
+
+	// RotationStatus rotate events of tracked rotations: pending, succeeded or escalated
+	RotationStatus *string `json:"rotation_status,omitempty"`
+	Safename       string  `json:"safename"`
+
+	// Source gg_webhook, hmsl_scan or cpm
+	Source string `json:"source"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Code    int32  `json:"code"`
@@ -38,18 +104,292 @@ type HashBatch struct {
 	Safename string `gorm:"primaryKey" json:"safename"`
 }
 
+// ImportResult defines model for ImportResult.
+type ImportResult struct {
+	// CreatedAt when hailstone wrote the export
+	CreatedAt time.Time `json:"created_at"`
+	Hashes    int       `json:"hashes"`
+	Safes     int       `json:"safes"`
+}
+
+// ReconcileResult defines model for ReconcileResult.
+type ReconcileResult struct {
+	// Checkpoint date of the last incident reconciled without failures; the next reconciliation resumes from it
+	Checkpoint *time.Time `json:"checkpoint,omitempty"`
+	Failed     int        `json:"failed"`
+	Listed     int        `json:"listed"`
+	Remediated int        `json:"remediated"`
+	Since      time.Time  `json:"since"`
+
+	// Skipped incidents already remediated or closed, without hmsl hash, or whose action is not remediated
+	Skipped int `json:"skipped"`
+}
+
+// RetentionPolicy defines model for RetentionPolicy.
+type RetentionPolicy struct {
+	// Default true when the safe has no saved policy
+	Default *bool `json:"default,omitempty"`
+
+	// Maxagedays older versions are also kept while younger than this many days, 0 disables
+	Maxagedays *int `json:"maxagedays,omitempty"`
+
+	// Maxhashcount number of hash versions kept per account, including the current one
+	Maxhashcount *int    `json:"maxhashcount,omitempty"`
+	Safename     *string `json:"safename,omitempty"`
+}
+
+// Rotation defines model for Rotation.
+type Rotation struct {
+	AccountId   string     `json:"account_id"`
+	Attempts    int        `json:"attempts"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Error why the last attempt failed
+	Error      *string `json:"error,omitempty"`
+	Id         int     `json:"id"`
+	IncidentId *int    `json:"incident_id,omitempty"`
+
+	// RequestedAt when the last attempt was requested
+	RequestedAt time.Time `json:"requested_at"`
+	Safename    string    `json:"safename"`
+
+	// Status pending, succeeded or escalated
+	Status string `json:"status"`
+}
+
+// SafeSummary defines model for SafeSummary.
+type SafeSummary struct {
+	Accountcount int        `json:"accountcount"`
+	Hashcount    int        `json:"hashcount"`
+	LastUpdated  *time.Time `json:"last_updated,omitempty"`
+	Safename     string     `json:"safename"`
+}
+
+// ScanJobLeakStatus defines model for ScanJobLeakStatus.
+type ScanJobLeakStatus struct {
+	Count    *int    `json:"count,omitempty"`
+	Error    *string `json:"error,omitempty"`
+	Hash     string  `json:"hash"`
+	Location *string `json:"location,omitempty"`
+	Rotated  bool    `json:"rotated"`
+	Safename string  `json:"safename"`
+}
+
+// ScanJobRequest defines model for ScanJobRequest.
+type ScanJobRequest struct {
+	// Mode send full hmsl-hashes (default) or hash prefixes to HMSL
+	Mode *ScanJobRequestMode `json:"mode,omitempty"`
+
+	// Safenames limit the scan to these safes, default is all safes
+	Safenames *[]string `json:"safenames,omitempty"`
+}
+
+// ScanJobRequestMode send full hmsl-hashes (default) or hash prefixes to HMSL
+type ScanJobRequestMode string
+
+// ScanJobSafeStatus defines model for ScanJobSafeStatus.
+type ScanJobSafeStatus struct {
+	Distinctcount *int    `json:"distinctcount,omitempty"`
+	Failedcount   *int    `json:"failedcount,omitempty"`
+	Invalidcount  *int    `json:"invalidcount,omitempty"`
+	Leakcount     *int    `json:"leakcount,omitempty"`
+	Message       *string `json:"message,omitempty"`
+	Rotatedcount  *int    `json:"rotatedcount,omitempty"`
+	Safename      string  `json:"safename"`
+	Sendcount     *int    `json:"sendcount,omitempty"`
+	Status        string  `json:"status"`
+	Totalcount    *int    `json:"totalcount,omitempty"`
+}
+
+// ScanJobStatus defines model for ScanJobStatus.
+type ScanJobStatus struct {
+	CreatedAt  time.Time            `json:"created_at"`
+	Error      *string              `json:"error,omitempty"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+	Id         int                  `json:"id"`
+	Leaks      *[]ScanJobLeakStatus `json:"leaks,omitempty"`
+	Mode       string               `json:"mode"`
+	Safenames  *[]string            `json:"safenames,omitempty"`
+	Safes      *[]ScanJobSafeStatus `json:"safes,omitempty"`
+	StartedAt  *time.Time           `json:"started_at,omitempty"`
+	Status     string               `json:"status"`
+}
+
+// Schedule defines model for Schedule.
+type Schedule struct {
+	// Cron cron expression, ex: "0 2 * * *"
+	Cron    *string `json:"cron,omitempty"`
+	Enabled *bool   `json:"enabled,omitempty"`
+	Id      *int    `json:"id,omitempty"`
+
+	// Interval interval between scans, ex: "6h"
+	Interval *string    `json:"interval,omitempty"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	Safename *string    `json:"safename,omitempty"`
+}
+
+// ScheduleRun defines model for ScheduleRun.
+type ScheduleRun struct {
+	Error        *string    `json:"error,omitempty"`
+	Failedcount  *int       `json:"failedcount,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	Id           int        `json:"id"`
+	Leakcount    *int       `json:"leakcount,omitempty"`
+	Rotatedcount *int       `json:"rotatedcount,omitempty"`
+	Safename     *string    `json:"safename,omitempty"`
+	ScheduleId   *int       `json:"schedule_id,omitempty"`
+	Sendcount    *int       `json:"sendcount,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	Status       string     `json:"status"`
+}
+
+// StoredHash defines model for StoredHash.
+type StoredHash struct {
+	Accountid string    `json:"accountid"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Hash full hmsl-hash, only returned to the admin api key
+	Hash       *string `json:"hash,omitempty"`
+	HashPrefix string  `json:"hash_prefix"`
+	Safename   string  `json:"safename"`
+}
+
+// StoredHashPage defines model for StoredHashPage.
+type StoredHashPage struct {
+	Hashes   []StoredHash `json:"hashes"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int          `json:"total"`
+}
+
+// WriteBackPolicy defines model for WriteBackPolicy.
+type WriteBackPolicy struct {
+	// Assignee email of the GG member the remediated incidents are assigned to, empty string disables
+	Assignee *string `json:"assignee,omitempty"`
+
+	// Default true when the safe has no saved write-back policy, nothing is written back
+	Default *bool `json:"default,omitempty"`
+
+	// Mode after remediating accounts of the safe, leave the GG incident untouched, post a note, or post the note and resolve the incident once every rotation succeeded
+	Mode     *WriteBackPolicyMode `json:"mode,omitempty"`
+	Safename *string              `json:"safename,omitempty"`
+}
+
+// WriteBackPolicyMode after remediating accounts of the safe, leave the GG incident untouched, post a note, or post the note and resolve the incident once every rotation succeeded
+type WriteBackPolicyMode string
+
+// AuditGetParams defines parameters for AuditGet.
+type AuditGetParams struct {
+	Safename  *string               `form:"safename,omitempty" json:"safename,omitempty"`
+	Accountid *string               `form:"accountid,omitempty" json:"accountid,omitempty"`
+	Source    *AuditGetParamsSource `form:"source,omitempty" json:"source,omitempty"`
+	From      *time.Time            `form:"from,omitempty" json:"from,omitempty"`
+	To        *time.Time            `form:"to,omitempty" json:"to,omitempty"`
+	Limit     *int                  `form:"limit,omitempty" json:"limit,omitempty"`
+	Format    *AuditGetParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// AuditGetParamsSource defines parameters for AuditGet.
+type AuditGetParamsSource string
+
+// AuditGetParamsFormat defines parameters for AuditGet.
+type AuditGetParamsFormat string
+
+// HashesGetParams defines parameters for HashesGet.
+type HashesGetParams struct {
+	Safename  *string `form:"safename,omitempty" json:"safename,omitempty"`
+	Accountid *string `form:"accountid,omitempty" json:"accountid,omitempty"`
+
+	// Page page number, starting at 1
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// PageSize hashes per page, default 100, max 1000
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
+
+	// Full include the full hmsl-hashes, requires the admin api key
+	Full *bool `form:"full,omitempty" json:"full,omitempty"`
+}
+
 // HashesPutJSONBody defines parameters for HashesPut.
 type HashesPutJSONBody = []HashBatch
 
+// SendFullHashesGetParams defines parameters for SendFullHashesGet.
+type SendFullHashesGetParams struct {
+	// DryRun report the planned remediation without changing PAM or the brimstone database
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// IncidentsReconcilePostParams defines parameters for IncidentsReconcilePost.
+type IncidentsReconcilePostParams struct {
+	// Since start date (RFC3339), required until a first reconciliation saved a checkpoint
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Status GG incident status to reconcile, default TRIGGERED
+	Status *IncidentsReconcilePostParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// DryRun report the planned remediation without changing PAM or the brimstone database
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// IncidentsReconcilePostParamsStatus defines parameters for IncidentsReconcilePost.
+type IncidentsReconcilePostParamsStatus string
+
 // CyberArkPAMCPMEventPutJSONBody defines parameters for CyberArkPAMCPMEventPut.
 type CyberArkPAMCPMEventPutJSONBody = []HashBatch
 
+// CyberArkPAMCPMEventPutParams defines parameters for CyberArkPAMCPMEventPut.
+type CyberArkPAMCPMEventPutParams struct {
+	// DryRun report the planned remediation without changing PAM or the brimstone database
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// GitGuardianEventPostParams defines parameters for GitGuardianEventPost.
+type GitGuardianEventPostParams struct {
+	// DryRun report the planned remediation without changing PAM or the brimstone database
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
+// RotationsGetParams defines parameters for RotationsGet.
+type RotationsGetParams struct {
+	Safename *string                   `form:"safename,omitempty" json:"safename,omitempty"`
+	Status   *RotationsGetParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	Limit    *int                      `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// RotationsGetParamsStatus defines parameters for RotationsGet.
+type RotationsGetParamsStatus string
+
+// ScheduleRunsGetParams defines parameters for ScheduleRunsGet.
+type ScheduleRunsGetParams struct {
+	Safename *string `form:"safename,omitempty" json:"safename,omitempty"`
+	Limit    *int    `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ScanJobsGetParams defines parameters for ScanJobsGet.
+type ScanJobsGetParams struct {
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // HashesPutJSONRequestBody defines body for HashesPut for application/json ContentType.
 type HashesPutJSONRequestBody = HashesPutJSONBody
 
 // CyberArkPAMCPMEventPutJSONRequestBody defines body for CyberArkPAMCPMEventPut for application/json ContentType.
 type CyberArkPAMCPMEventPutJSONRequestBody = CyberArkPAMCPMEventPutJSONBody
 
+// SafePolicyPutJSONRequestBody defines body for SafePolicyPut for application/json ContentType.
+type SafePolicyPutJSONRequestBody = RetentionPolicy
+
+// SafeWriteBackPutJSONRequestBody defines body for SafeWriteBackPut for application/json ContentType.
+type SafeWriteBackPutJSONRequestBody = WriteBackPolicy
+
+// SchedulesPostJSONRequestBody defines body for SchedulesPost for application/json ContentType.
+type SchedulesPostJSONRequestBody = Schedule
+
+// ScanJobsPostJSONRequestBody defines body for ScanJobsPost for application/json ContentType.
+type ScanJobsPostJSONRequestBody = ScanJobRequest
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
@@ -123,28 +463,102 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// AuditGet request
+	AuditGet(ctx context.Context, params *AuditGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// HashesGet request
+	HashesGet(ctx context.Context, params *HashesGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// HashesPutWithBody request with any body
 	HashesPutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	HashesPut(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// HashesImportPostWithBody request with any body
+	HashesImportPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// SendFullHashesGet request
-	SendFullHashesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+	SendFullHashesGet(ctx context.Context, params *SendFullHashesGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// SendHashPrefixesGet request
 	SendHashPrefixesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// IncidentsReconcilePost request
+	IncidentsReconcilePost(ctx context.Context, params *IncidentsReconcilePostParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// CyberArkPAMCPMEventPutWithBody request with any body
-	CyberArkPAMCPMEventPutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+	CyberArkPAMCPMEventPutWithBody(ctx context.Context, params *CyberArkPAMCPMEventPutParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
-	CyberArkPAMCPMEventPut(ctx context.Context, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+	CyberArkPAMCPMEventPut(ctx context.Context, params *CyberArkPAMCPMEventPutParams, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// GitGuardianEventPost request
-	GitGuardianEventPost(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+	GitGuardianEventPost(ctx context.Context, params *GitGuardianEventPostParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafePoliciesGet request
+	SafePoliciesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RotationsGet request
+	RotationsGet(ctx context.Context, params *RotationsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafesGet request
+	SafesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafeDelete request
+	SafeDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafeAccountDelete request
+	SafeAccountDelete(ctx context.Context, safename string, accountid string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafePolicyDelete request
+	SafePolicyDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafePolicyGet request
+	SafePolicyGet(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafePolicyPutWithBody request with any body
+	SafePolicyPutWithBody(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SafePolicyPut(ctx context.Context, safename string, body SafePolicyPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafeWriteBackDelete request
+	SafeWriteBackDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafeWriteBackGet request
+	SafeWriteBackGet(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SafeWriteBackPutWithBody request with any body
+	SafeWriteBackPutWithBody(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SafeWriteBackPut(ctx context.Context, safename string, body SafeWriteBackPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SchedulesGet request
+	SchedulesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// SchedulesPostWithBody request with any body
+	SchedulesPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	SchedulesPost(ctx context.Context, body SchedulesPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ScheduleRunsGet request
+	ScheduleRunsGet(ctx context.Context, params *ScheduleRunsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ScheduleDelete request
+	ScheduleDelete(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ScanJobsGet request
+	ScanJobsGet(ctx context.Context, params *ScanJobsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ScanJobsPostWithBody request with any body
+	ScanJobsPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	ScanJobsPost(ctx context.Context, body ScanJobsPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ScanJobGet request
+	ScanJobGet(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
-func (c *Client) HashesPutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewHashesPutRequestWithBody(c.Server, contentType, body)
+func (c *Client) AuditGet(ctx context.Context, params *AuditGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAuditGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -155,8 +569,8 @@ func (c *Client) HashesPutWithBody(ctx context.Context, contentType string, body
 	return c.Client.Do(req)
 }
 
-func (c *Client) HashesPut(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewHashesPutRequest(c.Server, body)
+func (c *Client) HashesGet(ctx context.Context, params *HashesGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHashesGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +581,8 @@ func (c *Client) HashesPut(ctx context.Context, body HashesPutJSONRequestBody, r
 	return c.Client.Do(req)
 }
 
-func (c *Client) SendFullHashesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewSendFullHashesGetRequest(c.Server)
+func (c *Client) HashesPutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHashesPutRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -179,8 +593,8 @@ func (c *Client) SendFullHashesGet(ctx context.Context, reqEditors ...RequestEdi
 	return c.Client.Do(req)
 }
 
-func (c *Client) SendHashPrefixesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewSendHashPrefixesGetRequest(c.Server)
+func (c *Client) HashesPut(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHashesPutRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
@@ -191,8 +605,8 @@ func (c *Client) SendHashPrefixesGet(ctx context.Context, reqEditors ...RequestE
 	return c.Client.Do(req)
 }
 
-func (c *Client) CyberArkPAMCPMEventPutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewCyberArkPAMCPMEventPutRequestWithBody(c.Server, contentType, body)
+func (c *Client) HashesImportPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHashesImportPostRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
@@ -203,8 +617,8 @@ func (c *Client) CyberArkPAMCPMEventPutWithBody(ctx context.Context, contentType
 	return c.Client.Do(req)
 }
 
-func (c *Client) CyberArkPAMCPMEventPut(ctx context.Context, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewCyberArkPAMCPMEventPutRequest(c.Server, body)
+func (c *Client) SendFullHashesGet(ctx context.Context, params *SendFullHashesGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSendFullHashesGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
@@ -215,8 +629,8 @@ func (c *Client) CyberArkPAMCPMEventPut(ctx context.Context, body CyberArkPAMCPM
 	return c.Client.Do(req)
 }
 
-func (c *Client) GitGuardianEventPost(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
-	req, err := NewGitGuardianEventPostRequest(c.Server)
+func (c *Client) SendHashPrefixesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSendHashPrefixesGetRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
@@ -227,430 +641,3820 @@ func (c *Client) GitGuardianEventPost(ctx context.Context, reqEditors ...Request
 	return c.Client.Do(req)
 }
 
-// NewHashesPutRequest calls the generic HashesPut builder with application/json body
-func NewHashesPutRequest(server string, body HashesPutJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) IncidentsReconcilePost(ctx context.Context, params *IncidentsReconcilePostParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewIncidentsReconcilePostRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewHashesPutRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewHashesPutRequestWithBody generates requests for HashesPut with any type of body
-func NewHashesPutRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) CyberArkPAMCPMEventPutWithBody(ctx context.Context, params *CyberArkPAMCPMEventPutParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCyberArkPAMCPMEventPutRequestWithBody(c.Server, params, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/v1/hashes")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) CyberArkPAMCPMEventPut(ctx context.Context, params *CyberArkPAMCPMEventPutParams, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCyberArkPAMCPMEventPutRequest(c.Server, params, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("PUT", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewSendFullHashesGetRequest generates requests for SendFullHashesGet
-func NewSendFullHashesGetRequest(server string) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) GitGuardianEventPost(ctx context.Context, params *GitGuardianEventPostParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGitGuardianEventPostRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/v1/hashes/sendhashes")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) SafePoliciesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafePoliciesGetRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewSendHashPrefixesGetRequest generates requests for SendHashPrefixesGet
-func NewSendHashPrefixesGetRequest(server string) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) RotationsGet(ctx context.Context, params *RotationsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRotationsGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/v1/hashes/sendprefixes")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) SafesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafesGetRequest(c.Server)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewCyberArkPAMCPMEventPutRequest calls the generic CyberArkPAMCPMEventPut builder with application/json body
-func NewCyberArkPAMCPMEventPutRequest(server string, body CyberArkPAMCPMEventPutJSONRequestBody) (*http.Request, error) {
-	var bodyReader io.Reader
-	buf, err := json.Marshal(body)
+func (c *Client) SafeDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeDeleteRequest(c.Server, safename)
 	if err != nil {
 		return nil, err
 	}
-	bodyReader = bytes.NewReader(buf)
-	return NewCyberArkPAMCPMEventPutRequestWithBody(server, "application/json", bodyReader)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// NewCyberArkPAMCPMEventPutRequestWithBody generates requests for CyberArkPAMCPMEventPut with any type of body
-func NewCyberArkPAMCPMEventPutRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) SafeAccountDelete(ctx context.Context, safename string, accountid string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeAccountDeleteRequest(c.Server, safename, accountid)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/v1/notify/cybrcpmevent")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) SafePolicyDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafePolicyDeleteRequest(c.Server, safename)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("PUT", queryURL.String(), body)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-// NewGitGuardianEventPostRequest generates requests for GitGuardianEventPost
-func NewGitGuardianEventPostRequest(server string) (*http.Request, error) {
-	var err error
-
-	serverURL, err := url.Parse(server)
+func (c *Client) SafePolicyGet(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafePolicyGetRequest(c.Server, safename)
 	if err != nil {
 		return nil, err
 	}
-
-	operationPath := fmt.Sprintf("/v1/notify/ggevent")
-	if operationPath[0] == '/' {
-		operationPath = "." + operationPath
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
+}
 
-	queryURL, err := serverURL.Parse(operationPath)
+func (c *Client) SafePolicyPutWithBody(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafePolicyPutRequestWithBody(c.Server, safename, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), nil)
-	if err != nil {
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
 		return nil, err
 	}
-
-	return req, nil
+	return c.Client.Do(req)
 }
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
+func (c *Client) SafePolicyPut(ctx context.Context, safename string, body SafePolicyPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafePolicyPutRequest(c.Server, safename, body)
+	if err != nil {
+		return nil, err
 	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
-		}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
+	return c.Client.Do(req)
 }
 
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+func (c *Client) SafeWriteBackDelete(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeWriteBackDeleteRequest(c.Server, safename)
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
-
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
-		newBaseURL, err := url.Parse(baseURL)
-		if err != nil {
-			return err
-		}
-		c.Server = newBaseURL.String()
-		return nil
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
+	return c.Client.Do(req)
 }
 
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// HashesPutWithBodyWithResponse request with any body
-	HashesPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesPutResponse, error)
-
-	HashesPutWithResponse(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*HashesPutResponse, error)
-
-	// SendFullHashesGetWithResponse request
-	SendFullHashesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendFullHashesGetResponse, error)
-
-	// SendHashPrefixesGetWithResponse request
-	SendHashPrefixesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendHashPrefixesGetResponse, error)
-
-	// CyberArkPAMCPMEventPutWithBodyWithResponse request with any body
-	CyberArkPAMCPMEventPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error)
-
-	CyberArkPAMCPMEventPutWithResponse(ctx context.Context, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error)
-
-	// GitGuardianEventPostWithResponse request
-	GitGuardianEventPostWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GitGuardianEventPostResponse, error)
-}
-
-type HashesPutResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *string
-	JSON401      *string
-	JSONDefault  *Error
-}
-
-// Status returns HTTPResponse.Status
-func (r HashesPutResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *Client) SafeWriteBackGet(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeWriteBackGetRequest(c.Server, safename)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
-
-// StatusCode returns HTTPResponse.StatusCode
-func (r HashesPutResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return 0
-}
-
-type SendFullHashesGetResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *string
-	JSON401      *string
-	JSONDefault  *Error
+	return c.Client.Do(req)
 }
 
-// Status returns HTTPResponse.Status
-func (r SendFullHashesGetResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *Client) SafeWriteBackPutWithBody(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeWriteBackPutRequestWithBody(c.Server, safename, contentType, body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
-
-// StatusCode returns HTTPResponse.StatusCode
-func (r SendFullHashesGetResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return 0
-}
-
-type SendHashPrefixesGetResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *string
-	JSON401      *string
-	JSONDefault  *Error
+	return c.Client.Do(req)
 }
 
-// Status returns HTTPResponse.Status
-func (r SendHashPrefixesGetResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *Client) SafeWriteBackPut(ctx context.Context, safename string, body SafeWriteBackPutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSafeWriteBackPutRequest(c.Server, safename, body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
-
-// StatusCode returns HTTPResponse.StatusCode
-func (r SendHashPrefixesGetResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return 0
-}
-
-type CyberArkPAMCPMEventPutResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *string
-	JSON401      *string
-	JSONDefault  *Error
+	return c.Client.Do(req)
 }
 
-// Status returns HTTPResponse.Status
-func (r CyberArkPAMCPMEventPutResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *Client) SchedulesGet(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSchedulesGetRequest(c.Server)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
-
-// StatusCode returns HTTPResponse.StatusCode
-func (r CyberArkPAMCPMEventPutResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return 0
-}
-
-type GitGuardianEventPostResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON200      *string
-	JSON401      *string
-	JSONDefault  *Error
+	return c.Client.Do(req)
 }
 
-// Status returns HTTPResponse.Status
-func (r GitGuardianEventPostResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+func (c *Client) SchedulesPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSchedulesPostRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
 	}
-	return http.StatusText(0)
-}
-
-// StatusCode returns HTTPResponse.StatusCode
-func (r GitGuardianEventPostResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
 	}
-	return 0
+	return c.Client.Do(req)
 }
 
-// HashesPutWithBodyWithResponse request with arbitrary body returning *HashesPutResponse
-func (c *ClientWithResponses) HashesPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesPutResponse, error) {
-	rsp, err := c.HashesPutWithBody(ctx, contentType, body, reqEditors...)
+func (c *Client) SchedulesPost(ctx context.Context, body SchedulesPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSchedulesPostRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-	return ParseHashesPutResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-func (c *ClientWithResponses) HashesPutWithResponse(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*HashesPutResponse, error) {
-	rsp, err := c.HashesPut(ctx, body, reqEditors...)
+func (c *Client) ScheduleRunsGet(ctx context.Context, params *ScheduleRunsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScheduleRunsGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-	return ParseHashesPutResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// SendFullHashesGetWithResponse request returning *SendFullHashesGetResponse
-func (c *ClientWithResponses) SendFullHashesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendFullHashesGetResponse, error) {
-	rsp, err := c.SendFullHashesGet(ctx, reqEditors...)
+func (c *Client) ScheduleDelete(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScheduleDeleteRequest(c.Server, id)
 	if err != nil {
 		return nil, err
 	}
-	return ParseSendFullHashesGetResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// SendHashPrefixesGetWithResponse request returning *SendHashPrefixesGetResponse
-func (c *ClientWithResponses) SendHashPrefixesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendHashPrefixesGetResponse, error) {
-	rsp, err := c.SendHashPrefixesGet(ctx, reqEditors...)
+func (c *Client) ScanJobsGet(ctx context.Context, params *ScanJobsGetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScanJobsGetRequest(c.Server, params)
 	if err != nil {
 		return nil, err
 	}
-	return ParseSendHashPrefixesGetResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// CyberArkPAMCPMEventPutWithBodyWithResponse request with arbitrary body returning *CyberArkPAMCPMEventPutResponse
-func (c *ClientWithResponses) CyberArkPAMCPMEventPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error) {
-	rsp, err := c.CyberArkPAMCPMEventPutWithBody(ctx, contentType, body, reqEditors...)
+func (c *Client) ScanJobsPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScanJobsPostRequestWithBody(c.Server, contentType, body)
 	if err != nil {
 		return nil, err
 	}
-	return ParseCyberArkPAMCPMEventPutResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-func (c *ClientWithResponses) CyberArkPAMCPMEventPutWithResponse(ctx context.Context, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error) {
-	rsp, err := c.CyberArkPAMCPMEventPut(ctx, body, reqEditors...)
+func (c *Client) ScanJobsPost(ctx context.Context, body ScanJobsPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScanJobsPostRequest(c.Server, body)
 	if err != nil {
 		return nil, err
 	}
-	return ParseCyberArkPAMCPMEventPutResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// GitGuardianEventPostWithResponse request returning *GitGuardianEventPostResponse
-func (c *ClientWithResponses) GitGuardianEventPostWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GitGuardianEventPostResponse, error) {
-	rsp, err := c.GitGuardianEventPost(ctx, reqEditors...)
+func (c *Client) ScanJobGet(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewScanJobGetRequest(c.Server, id)
 	if err != nil {
 		return nil, err
 	}
-	return ParseGitGuardianEventPostResponse(rsp)
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
-// ParseHashesPutResponse parses an HTTP response from a HashesPutWithResponse call
-func ParseHashesPutResponse(rsp *http.Response) (*HashesPutResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
+// NewAuditGetRequest generates requests for AuditGet
+func NewAuditGetRequest(server string, params *AuditGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &HashesPutResponse{
+	operationPath := fmt.Sprintf("/v1/audit")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Safename != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "safename", runtime.ParamLocationQuery, *params.Safename); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Accountid != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "accountid", runtime.ParamLocationQuery, *params.Accountid); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Source != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "source", runtime.ParamLocationQuery, *params.Source); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHashesGetRequest generates requests for HashesGet
+func NewHashesGetRequest(server string, params *HashesGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/hashes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Safename != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "safename", runtime.ParamLocationQuery, *params.Safename); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Accountid != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "accountid", runtime.ParamLocationQuery, *params.Accountid); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Page != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.PageSize != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page_size", runtime.ParamLocationQuery, *params.PageSize); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Full != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "full", runtime.ParamLocationQuery, *params.Full); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHashesPutRequest calls the generic HashesPut builder with application/json body
+func NewHashesPutRequest(server string, body HashesPutJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewHashesPutRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewHashesPutRequestWithBody generates requests for HashesPut with any type of body
+func NewHashesPutRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/hashes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewHashesImportPostRequestWithBody generates requests for HashesImportPost with any type of body
+func NewHashesImportPostRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/hashes/import")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewSendFullHashesGetRequest generates requests for SendFullHashesGet
+func NewSendFullHashesGetRequest(server string, params *SendFullHashesGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/hashes/sendhashes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.DryRun != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dry_run", runtime.ParamLocationQuery, *params.DryRun); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSendHashPrefixesGetRequest generates requests for SendHashPrefixesGet
+func NewSendHashPrefixesGetRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/hashes/sendprefixes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewIncidentsReconcilePostRequest generates requests for IncidentsReconcilePost
+func NewIncidentsReconcilePostRequest(server string, params *IncidentsReconcilePostParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/incidents/reconcile")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.DryRun != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dry_run", runtime.ParamLocationQuery, *params.DryRun); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCyberArkPAMCPMEventPutRequest calls the generic CyberArkPAMCPMEventPut builder with application/json body
+func NewCyberArkPAMCPMEventPutRequest(server string, params *CyberArkPAMCPMEventPutParams, body CyberArkPAMCPMEventPutJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCyberArkPAMCPMEventPutRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCyberArkPAMCPMEventPutRequestWithBody generates requests for CyberArkPAMCPMEventPut with any type of body
+func NewCyberArkPAMCPMEventPutRequestWithBody(server string, params *CyberArkPAMCPMEventPutParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/notify/cybrcpmevent")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.DryRun != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dry_run", runtime.ParamLocationQuery, *params.DryRun); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGitGuardianEventPostRequest generates requests for GitGuardianEventPost
+func NewGitGuardianEventPostRequest(server string, params *GitGuardianEventPostParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/notify/ggevent")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.DryRun != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dry_run", runtime.ParamLocationQuery, *params.DryRun); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafePoliciesGetRequest generates requests for SafePoliciesGet
+func NewSafePoliciesGetRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/policies")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewRotationsGetRequest generates requests for RotationsGet
+func NewRotationsGetRequest(server string, params *RotationsGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/rotations")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Safename != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "safename", runtime.ParamLocationQuery, *params.Safename); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafesGetRequest generates requests for SafesGet
+func NewSafesGetRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafeDeleteRequest generates requests for SafeDelete
+func NewSafeDeleteRequest(server string, safename string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafeAccountDeleteRequest generates requests for SafeAccountDelete
+func NewSafeAccountDeleteRequest(server string, safename string, accountid string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "accountid", runtime.ParamLocationPath, accountid)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/accounts/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafePolicyDeleteRequest generates requests for SafePolicyDelete
+func NewSafePolicyDeleteRequest(server string, safename string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/policy", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafePolicyGetRequest generates requests for SafePolicyGet
+func NewSafePolicyGetRequest(server string, safename string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/policy", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafePolicyPutRequest calls the generic SafePolicyPut builder with application/json body
+func NewSafePolicyPutRequest(server string, safename string, body SafePolicyPutJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSafePolicyPutRequestWithBody(server, safename, "application/json", bodyReader)
+}
+
+// NewSafePolicyPutRequestWithBody generates requests for SafePolicyPut with any type of body
+func NewSafePolicyPutRequestWithBody(server string, safename string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/policy", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewSafeWriteBackDeleteRequest generates requests for SafeWriteBackDelete
+func NewSafeWriteBackDeleteRequest(server string, safename string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/writeback", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafeWriteBackGetRequest generates requests for SafeWriteBackGet
+func NewSafeWriteBackGetRequest(server string, safename string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/writeback", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSafeWriteBackPutRequest calls the generic SafeWriteBackPut builder with application/json body
+func NewSafeWriteBackPutRequest(server string, safename string, body SafeWriteBackPutJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSafeWriteBackPutRequestWithBody(server, safename, "application/json", bodyReader)
+}
+
+// NewSafeWriteBackPutRequestWithBody generates requests for SafeWriteBackPut with any type of body
+func NewSafeWriteBackPutRequestWithBody(server string, safename string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "safename", runtime.ParamLocationPath, safename)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/safes/%s/writeback", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewSchedulesGetRequest generates requests for SchedulesGet
+func NewSchedulesGetRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/schedules")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSchedulesPostRequest calls the generic SchedulesPost builder with application/json body
+func NewSchedulesPostRequest(server string, body SchedulesPostJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewSchedulesPostRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewSchedulesPostRequestWithBody generates requests for SchedulesPost with any type of body
+func NewSchedulesPostRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/schedules")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewScheduleRunsGetRequest generates requests for ScheduleRunsGet
+func NewScheduleRunsGetRequest(server string, params *ScheduleRunsGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/schedules/runs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Safename != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "safename", runtime.ParamLocationQuery, *params.Safename); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewScheduleDeleteRequest generates requests for ScheduleDelete
+func NewScheduleDeleteRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/schedules/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewScanJobsGetRequest generates requests for ScanJobsGet
+func NewScanJobsGetRequest(server string, params *ScanJobsGetParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v2/scans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewScanJobsPostRequest calls the generic ScanJobsPost builder with application/json body
+func NewScanJobsPostRequest(server string, body ScanJobsPostJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewScanJobsPostRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewScanJobsPostRequestWithBody generates requests for ScanJobsPost with any type of body
+func NewScanJobsPostRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v2/scans")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewScanJobGetRequest generates requests for ScanJobGet
+func NewScanJobGetRequest(server string, id int) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v2/scans/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// AuditGetWithResponse request
+	AuditGetWithResponse(ctx context.Context, params *AuditGetParams, reqEditors ...RequestEditorFn) (*AuditGetResponse, error)
+
+	// HashesGetWithResponse request
+	HashesGetWithResponse(ctx context.Context, params *HashesGetParams, reqEditors ...RequestEditorFn) (*HashesGetResponse, error)
+
+	// HashesPutWithBodyWithResponse request with any body
+	HashesPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesPutResponse, error)
+
+	HashesPutWithResponse(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*HashesPutResponse, error)
+
+	// HashesImportPostWithBodyWithResponse request with any body
+	HashesImportPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesImportPostResponse, error)
+
+	// SendFullHashesGetWithResponse request
+	SendFullHashesGetWithResponse(ctx context.Context, params *SendFullHashesGetParams, reqEditors ...RequestEditorFn) (*SendFullHashesGetResponse, error)
+
+	// SendHashPrefixesGetWithResponse request
+	SendHashPrefixesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendHashPrefixesGetResponse, error)
+
+	// IncidentsReconcilePostWithResponse request
+	IncidentsReconcilePostWithResponse(ctx context.Context, params *IncidentsReconcilePostParams, reqEditors ...RequestEditorFn) (*IncidentsReconcilePostResponse, error)
+
+	// CyberArkPAMCPMEventPutWithBodyWithResponse request with any body
+	CyberArkPAMCPMEventPutWithBodyWithResponse(ctx context.Context, params *CyberArkPAMCPMEventPutParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error)
+
+	CyberArkPAMCPMEventPutWithResponse(ctx context.Context, params *CyberArkPAMCPMEventPutParams, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error)
+
+	// GitGuardianEventPostWithResponse request
+	GitGuardianEventPostWithResponse(ctx context.Context, params *GitGuardianEventPostParams, reqEditors ...RequestEditorFn) (*GitGuardianEventPostResponse, error)
+
+	// SafePoliciesGetWithResponse request
+	SafePoliciesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SafePoliciesGetResponse, error)
+
+	// RotationsGetWithResponse request
+	RotationsGetWithResponse(ctx context.Context, params *RotationsGetParams, reqEditors ...RequestEditorFn) (*RotationsGetResponse, error)
+
+	// SafesGetWithResponse request
+	SafesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SafesGetResponse, error)
+
+	// SafeDeleteWithResponse request
+	SafeDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeDeleteResponse, error)
+
+	// SafeAccountDeleteWithResponse request
+	SafeAccountDeleteWithResponse(ctx context.Context, safename string, accountid string, reqEditors ...RequestEditorFn) (*SafeAccountDeleteResponse, error)
+
+	// SafePolicyDeleteWithResponse request
+	SafePolicyDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafePolicyDeleteResponse, error)
+
+	// SafePolicyGetWithResponse request
+	SafePolicyGetWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafePolicyGetResponse, error)
+
+	// SafePolicyPutWithBodyWithResponse request with any body
+	SafePolicyPutWithBodyWithResponse(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SafePolicyPutResponse, error)
+
+	SafePolicyPutWithResponse(ctx context.Context, safename string, body SafePolicyPutJSONRequestBody, reqEditors ...RequestEditorFn) (*SafePolicyPutResponse, error)
+
+	// SafeWriteBackDeleteWithResponse request
+	SafeWriteBackDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeWriteBackDeleteResponse, error)
+
+	// SafeWriteBackGetWithResponse request
+	SafeWriteBackGetWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeWriteBackGetResponse, error)
+
+	// SafeWriteBackPutWithBodyWithResponse request with any body
+	SafeWriteBackPutWithBodyWithResponse(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SafeWriteBackPutResponse, error)
+
+	SafeWriteBackPutWithResponse(ctx context.Context, safename string, body SafeWriteBackPutJSONRequestBody, reqEditors ...RequestEditorFn) (*SafeWriteBackPutResponse, error)
+
+	// SchedulesGetWithResponse request
+	SchedulesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SchedulesGetResponse, error)
+
+	// SchedulesPostWithBodyWithResponse request with any body
+	SchedulesPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SchedulesPostResponse, error)
+
+	SchedulesPostWithResponse(ctx context.Context, body SchedulesPostJSONRequestBody, reqEditors ...RequestEditorFn) (*SchedulesPostResponse, error)
+
+	// ScheduleRunsGetWithResponse request
+	ScheduleRunsGetWithResponse(ctx context.Context, params *ScheduleRunsGetParams, reqEditors ...RequestEditorFn) (*ScheduleRunsGetResponse, error)
+
+	// ScheduleDeleteWithResponse request
+	ScheduleDeleteWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*ScheduleDeleteResponse, error)
+
+	// ScanJobsGetWithResponse request
+	ScanJobsGetWithResponse(ctx context.Context, params *ScanJobsGetParams, reqEditors ...RequestEditorFn) (*ScanJobsGetResponse, error)
+
+	// ScanJobsPostWithBodyWithResponse request with any body
+	ScanJobsPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ScanJobsPostResponse, error)
+
+	ScanJobsPostWithResponse(ctx context.Context, body ScanJobsPostJSONRequestBody, reqEditors ...RequestEditorFn) (*ScanJobsPostResponse, error)
+
+	// ScanJobGetWithResponse request
+	ScanJobGetWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*ScanJobGetResponse, error)
+}
+
+type AuditGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]AuditEvent
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r AuditGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AuditGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HashesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StoredHashPage
+	JSON401      *string
+	JSON403      *Error
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r HashesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HashesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HashesPutResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r HashesPutResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HashesPutResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HashesImportPostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ImportResult
+	JSON400      *Error
+	JSON401      *string
+	JSON409      *Error
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r HashesImportPostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HashesImportPostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SendFullHashesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SendFullHashesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SendFullHashesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SendHashPrefixesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SendHashPrefixesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SendHashPrefixesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type IncidentsReconcilePostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReconcileResult
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r IncidentsReconcilePostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r IncidentsReconcilePostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CyberArkPAMCPMEventPutResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CyberArkPAMCPMEventPutResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CyberArkPAMCPMEventPutResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GitGuardianEventPostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GitGuardianEventPostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GitGuardianEventPostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafePoliciesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]RetentionPolicy
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafePoliciesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafePoliciesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RotationsGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Rotation
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r RotationsGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RotationsGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]SafeSummary
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafeDeleteResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafeDeleteResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafeDeleteResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafeAccountDeleteResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafeAccountDeleteResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafeAccountDeleteResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafePolicyDeleteResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafePolicyDeleteResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafePolicyDeleteResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafePolicyGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RetentionPolicy
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafePolicyGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafePolicyGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafePolicyPutResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RetentionPolicy
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafePolicyPutResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafePolicyPutResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafeWriteBackDeleteResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafeWriteBackDeleteResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafeWriteBackDeleteResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafeWriteBackGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WriteBackPolicy
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafeWriteBackGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafeWriteBackGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SafeWriteBackPutResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *WriteBackPolicy
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SafeWriteBackPutResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SafeWriteBackPutResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SchedulesGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Schedule
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SchedulesGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SchedulesGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SchedulesPostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Schedule
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r SchedulesPostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SchedulesPostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScheduleRunsGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]ScheduleRun
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScheduleRunsGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScheduleRunsGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScheduleDeleteResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *string
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScheduleDeleteResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScheduleDeleteResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScanJobsGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]ScanJobStatus
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScanJobsGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScanJobsGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScanJobsPostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *ScanJobStatus
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScanJobsPostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScanJobsPostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ScanJobGetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ScanJobStatus
+	JSON401      *string
+	JSONDefault  *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ScanJobGetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ScanJobGetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// AuditGetWithResponse request returning *AuditGetResponse
+func (c *ClientWithResponses) AuditGetWithResponse(ctx context.Context, params *AuditGetParams, reqEditors ...RequestEditorFn) (*AuditGetResponse, error) {
+	rsp, err := c.AuditGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAuditGetResponse(rsp)
+}
+
+// HashesGetWithResponse request returning *HashesGetResponse
+func (c *ClientWithResponses) HashesGetWithResponse(ctx context.Context, params *HashesGetParams, reqEditors ...RequestEditorFn) (*HashesGetResponse, error) {
+	rsp, err := c.HashesGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHashesGetResponse(rsp)
+}
+
+// HashesPutWithBodyWithResponse request with arbitrary body returning *HashesPutResponse
+func (c *ClientWithResponses) HashesPutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesPutResponse, error) {
+	rsp, err := c.HashesPutWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHashesPutResponse(rsp)
+}
+
+func (c *ClientWithResponses) HashesPutWithResponse(ctx context.Context, body HashesPutJSONRequestBody, reqEditors ...RequestEditorFn) (*HashesPutResponse, error) {
+	rsp, err := c.HashesPut(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHashesPutResponse(rsp)
+}
+
+// HashesImportPostWithBodyWithResponse request with arbitrary body returning *HashesImportPostResponse
+func (c *ClientWithResponses) HashesImportPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*HashesImportPostResponse, error) {
+	rsp, err := c.HashesImportPostWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHashesImportPostResponse(rsp)
+}
+
+// SendFullHashesGetWithResponse request returning *SendFullHashesGetResponse
+func (c *ClientWithResponses) SendFullHashesGetWithResponse(ctx context.Context, params *SendFullHashesGetParams, reqEditors ...RequestEditorFn) (*SendFullHashesGetResponse, error) {
+	rsp, err := c.SendFullHashesGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSendFullHashesGetResponse(rsp)
+}
+
+// SendHashPrefixesGetWithResponse request returning *SendHashPrefixesGetResponse
+func (c *ClientWithResponses) SendHashPrefixesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SendHashPrefixesGetResponse, error) {
+	rsp, err := c.SendHashPrefixesGet(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSendHashPrefixesGetResponse(rsp)
+}
+
+// IncidentsReconcilePostWithResponse request returning *IncidentsReconcilePostResponse
+func (c *ClientWithResponses) IncidentsReconcilePostWithResponse(ctx context.Context, params *IncidentsReconcilePostParams, reqEditors ...RequestEditorFn) (*IncidentsReconcilePostResponse, error) {
+	rsp, err := c.IncidentsReconcilePost(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIncidentsReconcilePostResponse(rsp)
+}
+
+// CyberArkPAMCPMEventPutWithBodyWithResponse request with arbitrary body returning *CyberArkPAMCPMEventPutResponse
+func (c *ClientWithResponses) CyberArkPAMCPMEventPutWithBodyWithResponse(ctx context.Context, params *CyberArkPAMCPMEventPutParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error) {
+	rsp, err := c.CyberArkPAMCPMEventPutWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCyberArkPAMCPMEventPutResponse(rsp)
+}
+
+func (c *ClientWithResponses) CyberArkPAMCPMEventPutWithResponse(ctx context.Context, params *CyberArkPAMCPMEventPutParams, body CyberArkPAMCPMEventPutJSONRequestBody, reqEditors ...RequestEditorFn) (*CyberArkPAMCPMEventPutResponse, error) {
+	rsp, err := c.CyberArkPAMCPMEventPut(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCyberArkPAMCPMEventPutResponse(rsp)
+}
+
+// GitGuardianEventPostWithResponse request returning *GitGuardianEventPostResponse
+func (c *ClientWithResponses) GitGuardianEventPostWithResponse(ctx context.Context, params *GitGuardianEventPostParams, reqEditors ...RequestEditorFn) (*GitGuardianEventPostResponse, error) {
+	rsp, err := c.GitGuardianEventPost(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGitGuardianEventPostResponse(rsp)
+}
+
+// SafePoliciesGetWithResponse request returning *SafePoliciesGetResponse
+func (c *ClientWithResponses) SafePoliciesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SafePoliciesGetResponse, error) {
+	rsp, err := c.SafePoliciesGet(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafePoliciesGetResponse(rsp)
+}
+
+// RotationsGetWithResponse request returning *RotationsGetResponse
+func (c *ClientWithResponses) RotationsGetWithResponse(ctx context.Context, params *RotationsGetParams, reqEditors ...RequestEditorFn) (*RotationsGetResponse, error) {
+	rsp, err := c.RotationsGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRotationsGetResponse(rsp)
+}
+
+// SafesGetWithResponse request returning *SafesGetResponse
+func (c *ClientWithResponses) SafesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SafesGetResponse, error) {
+	rsp, err := c.SafesGet(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafesGetResponse(rsp)
+}
+
+// SafeDeleteWithResponse request returning *SafeDeleteResponse
+func (c *ClientWithResponses) SafeDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeDeleteResponse, error) {
+	rsp, err := c.SafeDelete(ctx, safename, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeDeleteResponse(rsp)
+}
+
+// SafeAccountDeleteWithResponse request returning *SafeAccountDeleteResponse
+func (c *ClientWithResponses) SafeAccountDeleteWithResponse(ctx context.Context, safename string, accountid string, reqEditors ...RequestEditorFn) (*SafeAccountDeleteResponse, error) {
+	rsp, err := c.SafeAccountDelete(ctx, safename, accountid, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeAccountDeleteResponse(rsp)
+}
+
+// SafePolicyDeleteWithResponse request returning *SafePolicyDeleteResponse
+func (c *ClientWithResponses) SafePolicyDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafePolicyDeleteResponse, error) {
+	rsp, err := c.SafePolicyDelete(ctx, safename, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafePolicyDeleteResponse(rsp)
+}
+
+// SafePolicyGetWithResponse request returning *SafePolicyGetResponse
+func (c *ClientWithResponses) SafePolicyGetWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafePolicyGetResponse, error) {
+	rsp, err := c.SafePolicyGet(ctx, safename, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafePolicyGetResponse(rsp)
+}
+
+// SafePolicyPutWithBodyWithResponse request with arbitrary body returning *SafePolicyPutResponse
+func (c *ClientWithResponses) SafePolicyPutWithBodyWithResponse(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SafePolicyPutResponse, error) {
+	rsp, err := c.SafePolicyPutWithBody(ctx, safename, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafePolicyPutResponse(rsp)
+}
+
+func (c *ClientWithResponses) SafePolicyPutWithResponse(ctx context.Context, safename string, body SafePolicyPutJSONRequestBody, reqEditors ...RequestEditorFn) (*SafePolicyPutResponse, error) {
+	rsp, err := c.SafePolicyPut(ctx, safename, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafePolicyPutResponse(rsp)
+}
+
+// SafeWriteBackDeleteWithResponse request returning *SafeWriteBackDeleteResponse
+func (c *ClientWithResponses) SafeWriteBackDeleteWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeWriteBackDeleteResponse, error) {
+	rsp, err := c.SafeWriteBackDelete(ctx, safename, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeWriteBackDeleteResponse(rsp)
+}
+
+// SafeWriteBackGetWithResponse request returning *SafeWriteBackGetResponse
+func (c *ClientWithResponses) SafeWriteBackGetWithResponse(ctx context.Context, safename string, reqEditors ...RequestEditorFn) (*SafeWriteBackGetResponse, error) {
+	rsp, err := c.SafeWriteBackGet(ctx, safename, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeWriteBackGetResponse(rsp)
+}
+
+// SafeWriteBackPutWithBodyWithResponse request with arbitrary body returning *SafeWriteBackPutResponse
+func (c *ClientWithResponses) SafeWriteBackPutWithBodyWithResponse(ctx context.Context, safename string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SafeWriteBackPutResponse, error) {
+	rsp, err := c.SafeWriteBackPutWithBody(ctx, safename, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeWriteBackPutResponse(rsp)
+}
+
+func (c *ClientWithResponses) SafeWriteBackPutWithResponse(ctx context.Context, safename string, body SafeWriteBackPutJSONRequestBody, reqEditors ...RequestEditorFn) (*SafeWriteBackPutResponse, error) {
+	rsp, err := c.SafeWriteBackPut(ctx, safename, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSafeWriteBackPutResponse(rsp)
+}
+
+// SchedulesGetWithResponse request returning *SchedulesGetResponse
+func (c *ClientWithResponses) SchedulesGetWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*SchedulesGetResponse, error) {
+	rsp, err := c.SchedulesGet(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchedulesGetResponse(rsp)
+}
+
+// SchedulesPostWithBodyWithResponse request with arbitrary body returning *SchedulesPostResponse
+func (c *ClientWithResponses) SchedulesPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SchedulesPostResponse, error) {
+	rsp, err := c.SchedulesPostWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchedulesPostResponse(rsp)
+}
+
+func (c *ClientWithResponses) SchedulesPostWithResponse(ctx context.Context, body SchedulesPostJSONRequestBody, reqEditors ...RequestEditorFn) (*SchedulesPostResponse, error) {
+	rsp, err := c.SchedulesPost(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchedulesPostResponse(rsp)
+}
+
+// ScheduleRunsGetWithResponse request returning *ScheduleRunsGetResponse
+func (c *ClientWithResponses) ScheduleRunsGetWithResponse(ctx context.Context, params *ScheduleRunsGetParams, reqEditors ...RequestEditorFn) (*ScheduleRunsGetResponse, error) {
+	rsp, err := c.ScheduleRunsGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScheduleRunsGetResponse(rsp)
+}
+
+// ScheduleDeleteWithResponse request returning *ScheduleDeleteResponse
+func (c *ClientWithResponses) ScheduleDeleteWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*ScheduleDeleteResponse, error) {
+	rsp, err := c.ScheduleDelete(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScheduleDeleteResponse(rsp)
+}
+
+// ScanJobsGetWithResponse request returning *ScanJobsGetResponse
+func (c *ClientWithResponses) ScanJobsGetWithResponse(ctx context.Context, params *ScanJobsGetParams, reqEditors ...RequestEditorFn) (*ScanJobsGetResponse, error) {
+	rsp, err := c.ScanJobsGet(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScanJobsGetResponse(rsp)
+}
+
+// ScanJobsPostWithBodyWithResponse request with arbitrary body returning *ScanJobsPostResponse
+func (c *ClientWithResponses) ScanJobsPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ScanJobsPostResponse, error) {
+	rsp, err := c.ScanJobsPostWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScanJobsPostResponse(rsp)
+}
+
+func (c *ClientWithResponses) ScanJobsPostWithResponse(ctx context.Context, body ScanJobsPostJSONRequestBody, reqEditors ...RequestEditorFn) (*ScanJobsPostResponse, error) {
+	rsp, err := c.ScanJobsPost(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScanJobsPostResponse(rsp)
+}
+
+// ScanJobGetWithResponse request returning *ScanJobGetResponse
+func (c *ClientWithResponses) ScanJobGetWithResponse(ctx context.Context, id int, reqEditors ...RequestEditorFn) (*ScanJobGetResponse, error) {
+	rsp, err := c.ScanJobGet(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScanJobGetResponse(rsp)
+}
+
+// ParseAuditGetResponse parses an HTTP response from a AuditGetWithResponse call
+func ParseAuditGetResponse(rsp *http.Response) (*AuditGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AuditGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []AuditEvent
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (text/csv) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParseHashesGetResponse parses an HTTP response from a HashesGetWithResponse call
+func ParseHashesGetResponse(rsp *http.Response) (*HashesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HashesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StoredHashPage
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseHashesPutResponse parses an HTTP response from a HashesPutWithResponse call
+func ParseHashesPutResponse(rsp *http.Response) (*HashesPutResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HashesPutResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseHashesImportPostResponse parses an HTTP response from a HashesImportPostWithResponse call
+func ParseHashesImportPostResponse(rsp *http.Response) (*HashesImportPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HashesImportPostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ImportResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSendFullHashesGetResponse parses an HTTP response from a SendFullHashesGetWithResponse call
+func ParseSendFullHashesGetResponse(rsp *http.Response) (*SendFullHashesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SendFullHashesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSendHashPrefixesGetResponse parses an HTTP response from a SendHashPrefixesGetWithResponse call
+func ParseSendHashPrefixesGetResponse(rsp *http.Response) (*SendHashPrefixesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SendHashPrefixesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseIncidentsReconcilePostResponse parses an HTTP response from a IncidentsReconcilePostWithResponse call
+func ParseIncidentsReconcilePostResponse(rsp *http.Response) (*IncidentsReconcilePostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &IncidentsReconcilePostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReconcileResult
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCyberArkPAMCPMEventPutResponse parses an HTTP response from a CyberArkPAMCPMEventPutWithResponse call
+func ParseCyberArkPAMCPMEventPutResponse(rsp *http.Response) (*CyberArkPAMCPMEventPutResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CyberArkPAMCPMEventPutResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGitGuardianEventPostResponse parses an HTTP response from a GitGuardianEventPostWithResponse call
+func ParseGitGuardianEventPostResponse(rsp *http.Response) (*GitGuardianEventPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GitGuardianEventPostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafePoliciesGetResponse parses an HTTP response from a SafePoliciesGetWithResponse call
+func ParseSafePoliciesGetResponse(rsp *http.Response) (*SafePoliciesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafePoliciesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []RetentionPolicy
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRotationsGetResponse parses an HTTP response from a RotationsGetWithResponse call
+func ParseRotationsGetResponse(rsp *http.Response) (*RotationsGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RotationsGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Rotation
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafesGetResponse parses an HTTP response from a SafesGetWithResponse call
+func ParseSafesGetResponse(rsp *http.Response) (*SafesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []SafeSummary
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafeDeleteResponse parses an HTTP response from a SafeDeleteWithResponse call
+func ParseSafeDeleteResponse(rsp *http.Response) (*SafeDeleteResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafeDeleteResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafeAccountDeleteResponse parses an HTTP response from a SafeAccountDeleteWithResponse call
+func ParseSafeAccountDeleteResponse(rsp *http.Response) (*SafeAccountDeleteResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafeAccountDeleteResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafePolicyDeleteResponse parses an HTTP response from a SafePolicyDeleteWithResponse call
+func ParseSafePolicyDeleteResponse(rsp *http.Response) (*SafePolicyDeleteResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafePolicyDeleteResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafePolicyGetResponse parses an HTTP response from a SafePolicyGetWithResponse call
+func ParseSafePolicyGetResponse(rsp *http.Response) (*SafePolicyGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafePolicyGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RetentionPolicy
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafePolicyPutResponse parses an HTTP response from a SafePolicyPutWithResponse call
+func ParseSafePolicyPutResponse(rsp *http.Response) (*SafePolicyPutResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafePolicyPutResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RetentionPolicy
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafeWriteBackDeleteResponse parses an HTTP response from a SafeWriteBackDeleteWithResponse call
+func ParseSafeWriteBackDeleteResponse(rsp *http.Response) (*SafeWriteBackDeleteResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafeWriteBackDeleteResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafeWriteBackGetResponse parses an HTTP response from a SafeWriteBackGetWithResponse call
+func ParseSafeWriteBackGetResponse(rsp *http.Response) (*SafeWriteBackGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafeWriteBackGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WriteBackPolicy
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSafeWriteBackPutResponse parses an HTTP response from a SafeWriteBackPutWithResponse call
+func ParseSafeWriteBackPutResponse(rsp *http.Response) (*SafeWriteBackPutResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SafeWriteBackPutResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest WriteBackPolicy
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSchedulesGetResponse parses an HTTP response from a SchedulesGetWithResponse call
+func ParseSchedulesGetResponse(rsp *http.Response) (*SchedulesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SchedulesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Schedule
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSchedulesPostResponse parses an HTTP response from a SchedulesPostWithResponse call
+func ParseSchedulesPostResponse(rsp *http.Response) (*SchedulesPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SchedulesPostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Schedule
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScheduleRunsGetResponse parses an HTTP response from a ScheduleRunsGetWithResponse call
+func ParseScheduleRunsGetResponse(rsp *http.Response) (*ScheduleRunsGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScheduleRunsGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []ScheduleRun
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScheduleDeleteResponse parses an HTTP response from a ScheduleDeleteWithResponse call
+func ParseScheduleDeleteResponse(rsp *http.Response) (*ScheduleDeleteResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScheduleDeleteResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScanJobsGetResponse parses an HTTP response from a ScanJobsGetWithResponse call
+func ParseScanJobsGetResponse(rsp *http.Response) (*ScanJobsGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScanJobsGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []ScanJobStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScanJobsPostResponse parses an HTTP response from a ScanJobsPostWithResponse call
+func ParseScanJobsPostResponse(rsp *http.Response) (*ScanJobsPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScanJobsPostResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest ScanJobStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
 		var dest string
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseScanJobGetResponse parses an HTTP response from a ScanJobGetWithResponse call
+func ParseScanJobGetResponse(rsp *http.Response) (*ScanJobGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScanJobGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ScanJobStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
 		response.JSON200 = &dest
 
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
@@ -660,254 +4464,673 @@ func ParseHashesPutResponse(rsp *http.Response) (*HashesPutResponse, error) {
 		}
 		response.JSON401 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
-		var dest Error
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSONDefault = &dest
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Query the remediation audit trail
+	// (GET /v1/audit)
+	AuditGet(ctx echo.Context, params AuditGetParams) error
+	// List stored hashes
+	// (GET /v1/hashes)
+	HashesGet(ctx echo.Context, params HashesGetParams) error
+	// Add new hashes
+	// (PUT /v1/hashes)
+	HashesPut(ctx echo.Context) error
+	// Import an offline hash export
+	// (POST /v1/hashes/import)
+	HashesImportPost(ctx echo.Context) error
+	// Trigger brimstone to send full hmsl-hashes to HMSL
+	// (GET /v1/hashes/sendhashes)
+	SendFullHashesGet(ctx echo.Context, params SendFullHashesGetParams) error
+	// Trigger brimstone to send hash prefixes to HMSL
+	// (GET /v1/hashes/sendprefixes)
+	SendHashPrefixesGet(ctx echo.Context) error
+	// Reconcile GG incidents missed by the webhook
+	// (POST /v1/incidents/reconcile)
+	IncidentsReconcilePost(ctx echo.Context, params IncidentsReconcilePostParams) error
+	// CyberArk PAM CPM Event
+	// (PUT /v1/notify/cybrcpmevent)
+	CyberArkPAMCPMEventPut(ctx echo.Context, params CyberArkPAMCPMEventPutParams) error
+	// Gitguardian event posted from webhooks
+	// (POST /v1/notify/ggevent)
+	GitGuardianEventPost(ctx echo.Context, params GitGuardianEventPostParams) error
+	// List safe hash retention policies
+	// (GET /v1/policies)
+	SafePoliciesGet(ctx echo.Context) error
+	// Query the tracked password rotations
+	// (GET /v1/rotations)
+	RotationsGet(ctx echo.Context, params RotationsGetParams) error
+	// List safes
+	// (GET /v1/safes)
+	SafesGet(ctx echo.Context) error
+	// Purge a safe
+	// (DELETE /v1/safes/{safename})
+	SafeDelete(ctx echo.Context, safename string) error
+	// Purge an account
+	// (DELETE /v1/safes/{safename}/accounts/{accountid})
+	SafeAccountDelete(ctx echo.Context, safename string, accountid string) error
+	// Reset the hash retention policy of a safe
+	// (DELETE /v1/safes/{safename}/policy)
+	SafePolicyDelete(ctx echo.Context, safename string) error
+	// Get the hash retention policy of a safe
+	// (GET /v1/safes/{safename}/policy)
+	SafePolicyGet(ctx echo.Context, safename string) error
+	// Set the hash retention policy of a safe
+	// (PUT /v1/safes/{safename}/policy)
+	SafePolicyPut(ctx echo.Context, safename string) error
+	// Reset the GG write-back policy of a safe
+	// (DELETE /v1/safes/{safename}/writeback)
+	SafeWriteBackDelete(ctx echo.Context, safename string) error
+	// Get the GG write-back policy of a safe
+	// (GET /v1/safes/{safename}/writeback)
+	SafeWriteBackGet(ctx echo.Context, safename string) error
+	// Set the GG write-back policy of a safe
+	// (PUT /v1/safes/{safename}/writeback)
+	SafeWriteBackPut(ctx echo.Context, safename string) error
+	// List leak scan schedules
+	// (GET /v1/schedules)
+	SchedulesGet(ctx echo.Context) error
+	// Create or update a leak scan schedule
+	// (POST /v1/schedules)
+	SchedulesPost(ctx echo.Context) error
+	// List leak scan run history
+	// (GET /v1/schedules/runs)
+	ScheduleRunsGet(ctx echo.Context, params ScheduleRunsGetParams) error
+	// Delete a leak scan schedule
+	// (DELETE /v1/schedules/{id})
+	ScheduleDelete(ctx echo.Context, id int) error
+	// List leak scan jobs
+	// (GET /v2/scans)
+	ScanJobsGet(ctx echo.Context, params ScanJobsGetParams) error
+	// Enqueue a leak scan job
+	// (POST /v2/scans)
+	ScanJobsPost(ctx echo.Context) error
+	// Leak scan job progress and results
+	// (GET /v2/scans/{id})
+	ScanJobGet(ctx echo.Context, id int) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// AuditGet converts echo context to params.
+func (w *ServerInterfaceWrapper) AuditGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AuditGetParams
+	// ------------- Optional query parameter "safename" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "safename", ctx.QueryParams(), &params.Safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
+	}
+
+	// ------------- Optional query parameter "accountid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "accountid", ctx.QueryParams(), &params.Accountid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountid: %s", err))
+	}
+
+	// ------------- Optional query parameter "source" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "source", ctx.QueryParams(), &params.Source)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter source: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuditGet(ctx, params)
+	return err
+}
+
+// HashesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) HashesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params HashesGetParams
+	// ------------- Optional query parameter "safename" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "safename", ctx.QueryParams(), &params.Safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
+	}
+
+	// ------------- Optional query parameter "accountid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "accountid", ctx.QueryParams(), &params.Accountid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountid: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", ctx.QueryParams(), &params.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page_size: %s", err))
+	}
+
+	// ------------- Optional query parameter "full" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "full", ctx.QueryParams(), &params.Full)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter full: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.HashesGet(ctx, params)
+	return err
+}
+
+// HashesPut converts echo context to params.
+func (w *ServerInterfaceWrapper) HashesPut(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.HashesPut(ctx)
+	return err
+}
+
+// HashesImportPost converts echo context to params.
+func (w *ServerInterfaceWrapper) HashesImportPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.HashesImportPost(ctx)
+	return err
+}
+
+// SendFullHashesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SendFullHashesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SendFullHashesGetParams
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SendFullHashesGet(ctx, params)
+	return err
+}
+
+// SendHashPrefixesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SendHashPrefixesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SendHashPrefixesGet(ctx)
+	return err
+}
+
+// IncidentsReconcilePost converts echo context to params.
+func (w *ServerInterfaceWrapper) IncidentsReconcilePost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params IncidentsReconcilePostParams
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", ctx.QueryParams(), &params.Since)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.IncidentsReconcilePost(ctx, params)
+	return err
+}
+
+// CyberArkPAMCPMEventPut converts echo context to params.
+func (w *ServerInterfaceWrapper) CyberArkPAMCPMEventPut(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CyberArkPAMCPMEventPutParams
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CyberArkPAMCPMEventPut(ctx, params)
+	return err
+}
+
+// GitGuardianEventPost converts echo context to params.
+func (w *ServerInterfaceWrapper) GitGuardianEventPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GitGuardianEventPostParams
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", ctx.QueryParams(), &params.DryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter dry_run: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GitGuardianEventPost(ctx, params)
+	return err
+}
+
+// SafePoliciesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SafePoliciesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafePoliciesGet(ctx)
+	return err
+}
+
+// RotationsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) RotationsGet(ctx echo.Context) error {
+	var err error
 
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RotationsGetParams
+	// ------------- Optional query parameter "safename" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "safename", ctx.QueryParams(), &params.Safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	return response, nil
-}
+	// ------------- Optional query parameter "status" -------------
 
-// ParseSendFullHashesGetResponse parses an HTTP response from a SendFullHashesGetWithResponse call
-func ParseSendFullHashesGetResponse(rsp *http.Response) (*SendFullHashesGetResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
 	if err != nil {
-		return nil, err
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
 	}
 
-	response := &SendFullHashesGetResponse{
-		Body:         bodyBytes,
-		HTTPResponse: rsp,
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
 	}
 
-	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RotationsGet(ctx, params)
+	return err
+}
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON401 = &dest
+// SafesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SafesGet(ctx echo.Context) error {
+	var err error
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
-		var dest Error
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSONDefault = &dest
+	ctx.Set(BearerAuthScopes, []string{})
 
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafesGet(ctx)
+	return err
+}
+
+// SafeDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) SafeDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	return response, nil
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafeDelete(ctx, safename)
+	return err
 }
 
-// ParseSendHashPrefixesGetResponse parses an HTTP response from a SendHashPrefixesGetWithResponse call
-func ParseSendHashPrefixesGetResponse(rsp *http.Response) (*SendHashPrefixesGetResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
+// SafeAccountDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) SafeAccountDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
 	if err != nil {
-		return nil, err
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	response := &SendHashPrefixesGetResponse{
-		Body:         bodyBytes,
-		HTTPResponse: rsp,
+	// ------------- Path parameter "accountid" -------------
+	var accountid string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "accountid", runtime.ParamLocationPath, ctx.Param("accountid"), &accountid)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter accountid: %s", err))
 	}
 
-	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
+	ctx.Set(BearerAuthScopes, []string{})
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON401 = &dest
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafeAccountDelete(ctx, safename, accountid)
+	return err
+}
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
-		var dest Error
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSONDefault = &dest
+// SafePolicyDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) SafePolicyDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
 
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	return response, nil
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafePolicyDelete(ctx, safename)
+	return err
 }
 
-// ParseCyberArkPAMCPMEventPutResponse parses an HTTP response from a CyberArkPAMCPMEventPutWithResponse call
-func ParseCyberArkPAMCPMEventPutResponse(rsp *http.Response) (*CyberArkPAMCPMEventPutResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
-	if err != nil {
-		return nil, err
-	}
+// SafePolicyGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SafePolicyGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
 
-	response := &CyberArkPAMCPMEventPutResponse{
-		Body:         bodyBytes,
-		HTTPResponse: rsp,
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
+	ctx.Set(BearerAuthScopes, []string{})
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON401 = &dest
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafePolicyGet(ctx, safename)
+	return err
+}
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
-		var dest Error
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSONDefault = &dest
+// SafePolicyPut converts echo context to params.
+func (w *ServerInterfaceWrapper) SafePolicyPut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
 
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	return response, nil
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafePolicyPut(ctx, safename)
+	return err
 }
 
-// ParseGitGuardianEventPostResponse parses an HTTP response from a GitGuardianEventPostWithResponse call
-func ParseGitGuardianEventPostResponse(rsp *http.Response) (*GitGuardianEventPostResponse, error) {
-	bodyBytes, err := io.ReadAll(rsp.Body)
-	defer func() { _ = rsp.Body.Close() }()
+// SafeWriteBackDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) SafeWriteBackDelete(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
 	if err != nil {
-		return nil, err
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	response := &GitGuardianEventPostResponse{
-		Body:         bodyBytes,
-		HTTPResponse: rsp,
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafeWriteBackDelete(ctx, safename)
+	return err
+}
+
+// SafeWriteBackGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SafeWriteBackGet(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
+	ctx.Set(BearerAuthScopes, []string{})
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
-		var dest string
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON401 = &dest
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafeWriteBackGet(ctx, safename)
+	return err
+}
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
-		var dest Error
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSONDefault = &dest
+// SafeWriteBackPut converts echo context to params.
+func (w *ServerInterfaceWrapper) SafeWriteBackPut(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "safename" -------------
+	var safename string
 
+	err = runtime.BindStyledParameterWithLocation("simple", false, "safename", runtime.ParamLocationPath, ctx.Param("safename"), &safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
 	}
 
-	return response, nil
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SafeWriteBackPut(ctx, safename)
+	return err
 }
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Add new hashes
-	// (PUT /v1/hashes)
-	HashesPut(ctx echo.Context) error
-	// Trigger brimstone to send full hmsl-hashes to HMSL
-	// (GET /v1/hashes/sendhashes)
-	SendFullHashesGet(ctx echo.Context) error
-	// Trigger brimstone to send hash prefixes to HMSL
-	// (GET /v1/hashes/sendprefixes)
-	SendHashPrefixesGet(ctx echo.Context) error
-	// CyberArk PAM CPM Event
-	// (PUT /v1/notify/cybrcpmevent)
-	CyberArkPAMCPMEventPut(ctx echo.Context) error
-	// Gitguardian event posted from webhooks
-	// (POST /v1/notify/ggevent)
-	GitGuardianEventPost(ctx echo.Context) error
+// SchedulesGet converts echo context to params.
+func (w *ServerInterfaceWrapper) SchedulesGet(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SchedulesGet(ctx)
+	return err
 }
 
-// ServerInterfaceWrapper converts echo contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler ServerInterface
+// SchedulesPost converts echo context to params.
+func (w *ServerInterfaceWrapper) SchedulesPost(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SchedulesPost(ctx)
+	return err
 }
 
-// HashesPut converts echo context to params.
-func (w *ServerInterfaceWrapper) HashesPut(ctx echo.Context) error {
+// ScheduleRunsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleRunsGet(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScheduleRunsGetParams
+	// ------------- Optional query parameter "safename" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "safename", ctx.QueryParams(), &params.Safename)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter safename: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.HashesPut(ctx)
+	err = w.Handler.ScheduleRunsGet(ctx, params)
 	return err
 }
 
-// SendFullHashesGet converts echo context to params.
-func (w *ServerInterfaceWrapper) SendFullHashesGet(ctx echo.Context) error {
+// ScheduleDelete converts echo context to params.
+func (w *ServerInterfaceWrapper) ScheduleDelete(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.SendFullHashesGet(ctx)
+	err = w.Handler.ScheduleDelete(ctx, id)
 	return err
 }
 
-// SendHashPrefixesGet converts echo context to params.
-func (w *ServerInterfaceWrapper) SendHashPrefixesGet(ctx echo.Context) error {
+// ScanJobsGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScanJobsGet(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScanJobsGetParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.SendHashPrefixesGet(ctx)
+	err = w.Handler.ScanJobsGet(ctx, params)
 	return err
 }
 
-// CyberArkPAMCPMEventPut converts echo context to params.
-func (w *ServerInterfaceWrapper) CyberArkPAMCPMEventPut(ctx echo.Context) error {
+// ScanJobsPost converts echo context to params.
+func (w *ServerInterfaceWrapper) ScanJobsPost(ctx echo.Context) error {
 	var err error
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.CyberArkPAMCPMEventPut(ctx)
+	err = w.Handler.ScanJobsPost(ctx)
 	return err
 }
 
-// GitGuardianEventPost converts echo context to params.
-func (w *ServerInterfaceWrapper) GitGuardianEventPost(ctx echo.Context) error {
+// ScanJobGet converts echo context to params.
+func (w *ServerInterfaceWrapper) ScanJobGet(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	ctx.Set(BearerAuthScopes, []string{})
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GitGuardianEventPost(ctx)
+	err = w.Handler.ScanJobGet(ctx, id)
 	return err
 }
 
@@ -939,10 +5162,32 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/v1/audit", wrapper.AuditGet)
+	router.GET(baseURL+"/v1/hashes", wrapper.HashesGet)
 	router.PUT(baseURL+"/v1/hashes", wrapper.HashesPut)
+	router.POST(baseURL+"/v1/hashes/import", wrapper.HashesImportPost)
 	router.GET(baseURL+"/v1/hashes/sendhashes", wrapper.SendFullHashesGet)
 	router.GET(baseURL+"/v1/hashes/sendprefixes", wrapper.SendHashPrefixesGet)
+	router.POST(baseURL+"/v1/incidents/reconcile", wrapper.IncidentsReconcilePost)
 	router.PUT(baseURL+"/v1/notify/cybrcpmevent", wrapper.CyberArkPAMCPMEventPut)
 	router.POST(baseURL+"/v1/notify/ggevent", wrapper.GitGuardianEventPost)
+	router.GET(baseURL+"/v1/policies", wrapper.SafePoliciesGet)
+	router.GET(baseURL+"/v1/rotations", wrapper.RotationsGet)
+	router.GET(baseURL+"/v1/safes", wrapper.SafesGet)
+	router.DELETE(baseURL+"/v1/safes/:safename", wrapper.SafeDelete)
+	router.DELETE(baseURL+"/v1/safes/:safename/accounts/:accountid", wrapper.SafeAccountDelete)
+	router.DELETE(baseURL+"/v1/safes/:safename/policy", wrapper.SafePolicyDelete)
+	router.GET(baseURL+"/v1/safes/:safename/policy", wrapper.SafePolicyGet)
+	router.PUT(baseURL+"/v1/safes/:safename/policy", wrapper.SafePolicyPut)
+	router.DELETE(baseURL+"/v1/safes/:safename/writeback", wrapper.SafeWriteBackDelete)
+	router.GET(baseURL+"/v1/safes/:safename/writeback", wrapper.SafeWriteBackGet)
+	router.PUT(baseURL+"/v1/safes/:safename/writeback", wrapper.SafeWriteBackPut)
+	router.GET(baseURL+"/v1/schedules", wrapper.SchedulesGet)
+	router.POST(baseURL+"/v1/schedules", wrapper.SchedulesPost)
+	router.GET(baseURL+"/v1/schedules/runs", wrapper.ScheduleRunsGet)
+	router.DELETE(baseURL+"/v1/schedules/:id", wrapper.ScheduleDelete)
+	router.GET(baseURL+"/v2/scans", wrapper.ScanJobsGet)
+	router.POST(baseURL+"/v2/scans", wrapper.ScanJobsPost)
+	router.GET(baseURL+"/v2/scans/:id", wrapper.ScanJobGet)
 
 }
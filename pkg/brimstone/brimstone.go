@@ -2,20 +2,22 @@ package brimstone
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"regexp"
 	"slices"
-	"sort"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
 	//"gorm.io/driver/sqlite"
 	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
 	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/metrics"
 	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
 
 	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/utils"
@@ -23,7 +25,7 @@ import (
 	//"github.com/labstack/echo/v4/middleware"
 )
 
-// current, then current-1 and current-2, so, 3 total
+// current, then current-1 and current-2, so, 3 total; default for safes without a SafePolicy
 const MAX_HASH_COUNT = 3
 
 type BaseConfig struct {
@@ -35,12 +37,39 @@ type BaseConfig struct {
 	PamPass     string `env:"PAM_PASS,required,unset"`
 
 	TlsSkipVerify bool `env:"TLS_SKIP_VERIFY" envDefault:"false"`
+	DryRun        bool `env:"DRY_RUN" envDefault:"false"`
+
+	HashPruneInterval   time.Duration `env:"HASH_PRUNE_INTERVAL" envDefault:"1h"`
+	GgWebhookMaxSkew    time.Duration `env:"GG_WEBHOOK_MAX_SKEW" envDefault:"5m"`
+	GgIncidentActions   string        `env:"GG_INCIDENT_ACTIONS" envDefault:"incident_triggered:remediate,new_occurrence:remediate,incident_reopened:remediate,incident_resolved:close,incident_ignored:close"`
+	GgReconcileInterval time.Duration `env:"GG_RECONCILE_INTERVAL" envDefault:"0"`
+
+	RotationPollInterval time.Duration `env:"ROTATION_POLL_INTERVAL" envDefault:"30s"`
+	RotationTimeout      time.Duration `env:"ROTATION_TIMEOUT" envDefault:"10m"`
+	RotationMaxAttempts  int           `env:"ROTATION_MAX_ATTEMPTS" envDefault:"3"`
+	RehashAfterRotation  bool          `env:"REHASH_AFTER_ROTATION" envDefault:"false"`
+
+	HashExportKey string `env:"HASH_EXPORT_KEY,unset"` // signs and verifies offline hash exports
 }
 
 type Brimstone struct {
-	Db         *gorm.DB
-	HMSLClient *hmsl.ClientWithResponses
-	PAMConfig  *pam.Config
+	Db                *gorm.DB
+	HMSLClient        *hmsl.ClientWithResponses
+	PAMSessions       *pam.SessionManager // shared PAM session token, refreshed before it expires
+	Scheduler         *Scheduler
+	DryRun            bool                    // report planned remediations without changing PAM or the db
+	PruneInterval     time.Duration           // how often hash versions outside the safe policies are removed
+	WebhookMaxSkew    time.Duration           // max age of GG webhook timestamps, 0 disables the check
+	GGClient          *gg.ClientWithResponses // writes remediation results back to the GG incidents, nil disables
+	IncidentActions   map[string]string       // GG incident action -> remediate, close or record; nil uses the defaults
+	ReconcileInterval time.Duration           // how often missed GG incidents are reconciled from the checkpoint, 0 disables
+
+	RotationPollInterval time.Duration // how often the CPM status of pending rotations is checked, 0 disables tracking
+	RotationTimeout      time.Duration // a rotation not confirmed within the timeout is retried
+	RotationMaxAttempts  int           // rotations failing every attempt are escalated
+	RehashAfterRotation  bool          // save the hash of the new password once a rotation is confirmed
+
+	HashExportKey []byte // verifies offline hash exports, empty disables the import
 }
 
 // PAM Vault Safe list of Hashes with accountid and accountname (de-normalized table)
@@ -58,6 +87,17 @@ type SendHashesStats struct {
 	LeakStats *[]hmsl.SecretResponse `json:"leakstats,omitempty"`
 }
 
+// Result of sending hashes to HMSL and rotating the leaked accounts
+type ScanResult struct {
+	Stats            SendHashesStats
+	SendCount        int
+	Responses        []hmsl.SecretResponse
+	ValidationErrors []error
+	RotatedCount     int
+	FailedCount      int
+	Planned          []AccountMetadata // dry-run only, the accounts that would be rotated
+}
+
 type SafeHashStats struct {
 	Safename      string    `json:"safename"`
 	TotalCount    int       `json:"sentcount"`
@@ -78,17 +118,30 @@ type AccountMetadata struct {
 	Rotated  bool   `json:"rotated"`
 	Added    bool   `json:"added"`
 	SafeName string `json:"safe_name"`
+	Action   string `json:"action,omitempty"`
+	DryRun   bool   `json:"dry_run,omitempty"`
+
+	RotationStatus string `json:"rotation_status,omitempty"` // pending until the CPM confirms the rotation
 }
 
 // InitializeDb calls auto-migrate to create tables, if needed
 func (b Brimstone) InitializeDb() error {
-	errAutoMigrate := b.Db.AutoMigrate(&SafeHash{})
+	errAutoMigrate := b.Db.AutoMigrate(&SafeHash{}, &ScanSchedule{}, &ScanRun{}, &ScanJob{}, &ScanJobSafe{}, &ScanJobLeak{}, &RemediationEvent{}, &SafePolicy{}, &SafeWriteBack{}, &WebhookDelivery{}, &Incident{}, &ReconcileCheckpoint{}, &AccountRotation{}, &SafeImport{})
 	if errAutoMigrate != nil {
 		return errAutoMigrate
 	}
 	return nil
 }
 
+// withDryRun - returns a copy of brimstone in dry-run mode when the request asks for it;
+// a server wide dry-run cannot be turned off by a request
+func (b Brimstone) withDryRun(dryrun *bool) Brimstone {
+	if dryrun != nil && *dryrun {
+		b.DryRun = true
+	}
+	return b
+}
+
 // sendBrimstoneError wraps sending of an error in the Error format, and
 // handling the failure to marshal that.
 func sendBrimstoneError(ctx echo.Context, code int, message string) error {
@@ -102,18 +155,23 @@ func sendBrimstoneError(ctx echo.Context, code int, message string) error {
 
 // HashesPut - PUT /v1/hashes
 func (b Brimstone) HashesPut(ctx echo.Context) error {
-	db := b.Db
-
 	var hashbatch HashBatch
 	err := ctx.Bind(&hashbatch)
 	if err != nil {
 		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for HashBatch")
 	}
 
+	return b.SaveHashBatch(hashbatch)
+}
+
+// SaveHashBatch - save the hashes of a new safe, or the next versions of an existing safe
+func (b Brimstone) SaveHashBatch(hashbatch HashBatch) error {
+	db := b.Db
+
 	var hashes []SafeHash
 	result := db.Limit(1).Where(&SafeHash{Safename: hashbatch.Safename}).Find(&hashes)
 	if result.RowsAffected != 0 {
-		return b.SaveExistingSafeHashes(ctx, hashbatch)
+		return b.SaveSafeHashVersions(hashbatch)
 	}
 
 	// new safe with new hashes
@@ -133,11 +191,19 @@ func (b Brimstone) HashesPut(ctx echo.Context) error {
 
 // SaveExistingSafeHashes - Safe already exists, save versions of hashes
 func (b Brimstone) SaveExistingSafeHashes(ctx echo.Context, batch HashBatch) error {
+	return b.SaveSafeHashVersions(batch)
+}
+
+// SaveSafeHashVersions - save the hashes of the batch as the next versions of the accounts of the
+// safe, hashes equal to the current version are ignored; does not need a request, used by the
+// handlers and the background rotation checks
+func (b Brimstone) SaveSafeHashVersions(batch HashBatch) error {
 	db := b.Db
 
-	// create a lookup dictionary from the name/hashes in the safe
+	// create a lookup dictionary from the name/hashes in the safe; oldest first, so, the
+	// lookup ends up with the current hash of each name
 	var hashes []SafeHash
-	db.Where(&SafeHash{Safename: batch.Safename}).Find(&hashes)
+	db.Where(&SafeHash{Safename: batch.Safename}).Order("created_at, id").Find(&hashes)
 	lookup := make(map[string]string)
 	for i := 0; i < len(hashes); i++ {
 		lookup[hashes[i].Name] = hashes[i].Hash
@@ -166,199 +232,370 @@ func (b Brimstone) SaveExistingSafeHashes(ctx echo.Context, batch HashBatch) err
 
 	// new name in the safe, so, just need to create them
 	if len(newhashes) > 0 {
-		db.CreateInBatches(newhashes, 100)
+		if result := db.CreateInBatches(newhashes, 100); result.Error != nil {
+			return result.Error
+		}
 	}
 
+	// older versions are kept for the safe's retention policy, see PruneSafeHashes
 	if len(existinghashes) > 0 {
-		for i := 0; i < len(existinghashes); i++ {
-			h := existinghashes[i]
-			_ = db.Create(&h)
-
-			// clean out extra hash records since we keep only MAX_HASH_COUNT records per safename-name
-
-			var found []SafeHash
-			result := db.Where(&SafeHash{Safename: h.Safename, Name: h.Name}).Find(&found)
-
-			if result.RowsAffected > MAX_HASH_COUNT {
-				// newest records first
-				sort.Slice(found, func(i, j int) bool {
-					return found[i].CreatedAt.After(found[j].CreatedAt)
-				})
-
-				// remove first 3 items so we don't delete them from the db
-				found = slices.Delete(found, 0, 3)
-				db.Unscoped().Delete(found)
-			}
+		if result := db.CreateInBatches(existinghashes, 100); result.Error != nil {
+			return result.Error
 		}
 	}
 
-	// err := ctx.JSON()
-
 	return nil
 }
 
 // SendHashPrefixesGet - GET /v1/hashes/sendprefixes
 func (b Brimstone) SendHashPrefixesGet(ctx echo.Context) error {
-	db := b.Db
+	unlock, ok := b.lockScan()
+	if !ok {
+		return sendBrimstoneError(ctx, http.StatusConflict, "A leak scan is already running")
+	}
+	defer unlock()
+
+	result, err := b.ScanPrefixes(context.TODO(), nil)
+	if err != nil {
+		return err
+	}
+
+	stats := result.Stats
+	stats.SendCount = result.SendCount
+	err = ctx.JSON(200, stats)
+	return err
+}
+
+// SendSafePrefixes - send the hash prefixes of a safe to HMSL in batches and return the leaked
+// hashes; only prefixes leave brimstone, the matches are decrypted locally with the full hashes
+func (b Brimstone) SendSafePrefixes(contextctx context.Context, safename string, stats *SendHashesStats) ([]hmsl.SecretResponse, error) {
 	hmslclient := b.HMSLClient
-	contextctx := context.TODO()
+	batchsize := 5
 
-	// Send batches of hashes grouped by safename
-	var safenames []SafeHash
-	db.Distinct("safename").Order("safename").Find(&safenames)
+	var hashesleaked []hmsl.SecretResponse
 
-	// start gathering the response stats
-	stats := SendHashesStats{
-		Status:    0,
-		SendCount: 0,
-		SafeStats: &[]SafeHashStats{},
+	prefixes, err := b.FetchPrefixes(safename, stats)
+	if err != nil {
+		return nil, err
 	}
-	batchsize := 5
 
-	// send batches of hashes to HMSL
-	// TODO: (low priority) use context.Context to store stats
-	for i := 0; i < len(safenames); i++ {
+	// if we have valid prefixes, then send them to HMSL
+	if stats.SendCount > 0 {
+		var batch []string
+		for i := 0; i < len((*prefixes)); i += batchsize {
+			batch = (*prefixes)[i:utils.MinInt(i+batchsize, len((*prefixes)))]
+			fmt.Println(batch)
+			prefixquery := hmsl.PrefixesQuery{Prefixes: &batch}
+			respHashes, respHashesErr := hmslclient.BatchPrefixesV1PrefixesPostWithResponse(contextctx, prefixquery)
+			if respHashesErr != nil {
+				return hashesleaked, respHashesErr
+			}
 
-		prefixes, err := b.FetchPrefixes(safenames[i].Safename, &stats)
-		if err != nil {
-			return err
-		}
-
-		// if we have valid prefixes, then send them to HMSL
-		if stats.SendCount > 0 {
-			var batch []string
-			for i := 0; i < len((*prefixes)); i += batchsize {
-				batch = (*prefixes)[i:utils.MinInt(i+batchsize, len((*prefixes)))]
-				fmt.Println(batch)
-				prefixquery := hmsl.PrefixesQuery{Prefixes: &batch}
-				respHashes, respHashesErr := hmslclient.BatchPrefixesV1PrefixesPostWithResponse(contextctx, prefixquery)
-				if respHashesErr != nil {
-					return respHashesErr
-				}
-
-				HandlePrefixes(batch, respHashes)
+			// every full hash of the safe sharing a prefix of the batch can be one of the matches
+			hashes, err := b.FetchHashesByPrefix(safename, batch)
+			if err != nil {
+				return hashesleaked, err
 			}
+
+			secretresponses, validationerrs := HandlePrefixes(hashes, respHashes)
+			for j := 0; j < len(validationerrs); j++ {
+				log.Printf("ERROR: prefix response for safe, %s: %s\n", safename, validationerrs[j].Error())
+			}
+			hashesleaked = append(hashesleaked, secretresponses...)
 		}
 	}
+	metrics.LeaksFound.WithLabelValues(safename, SCAN_MODE_PREFIX).Add(float64(len(hashesleaked)))
 
-	err := ctx.JSON(200, stats)
-	return err
+	return hashesleaked, nil
 }
 
 // SendFullHashesGet - GET /v1/hashes/sendhashes
-func (b Brimstone) SendFullHashesGet(ctx echo.Context) error {
-	db := b.Db
-	hmslclient := b.HMSLClient
-	contextctx := context.TODO()
+func (b Brimstone) SendFullHashesGet(ctx echo.Context, params SendFullHashesGetParams) error {
+	b = b.withDryRun(params.DryRun)
+
+	unlock, ok := b.lockScan()
+	if !ok {
+		return sendBrimstoneError(ctx, http.StatusConflict, "A leak scan is already running")
+	}
+	defer unlock()
 
-	// Send batches of hashes grouped by safename
-	var safenames []SafeHash
-	db.Distinct("safename").Order("safename").Find(&safenames)
+	result, err := b.ScanFullHashes(context.TODO(), nil)
+	if err != nil {
+		return err
+	}
+
+	var rsp = []struct {
+		Responses        []hmsl.SecretResponse
+		ValidationErrors []error
+		PlannedAccounts  []AccountMetadata `json:",omitempty"`
+	}{
+		{
+			Responses:        result.Responses,
+			ValidationErrors: result.ValidationErrors,
+			PlannedAccounts:  result.Planned,
+		},
+	}
+	err = ctx.JSON(200, rsp)
+	return err
+}
+
+// ScanFullHashes - send the full hmsl hashes of the safes (all safes when safenames is empty)
+// to HMSL and change the password of every account whose hash has leaked
+func (b Brimstone) ScanFullHashes(contextctx context.Context, safenames []string) (*ScanResult, error) {
+	return b.scanHashes(contextctx, SCAN_MODE_FULL, safenames)
+}
+
+// ScanPrefixes - send the hmsl hash prefixes of the safes (all safes when safenames is empty)
+// to HMSL and change the password of every account whose hash has leaked
+func (b Brimstone) ScanPrefixes(contextctx context.Context, safenames []string) (*ScanResult, error) {
+	return b.scanHashes(contextctx, SCAN_MODE_PREFIX, safenames)
+}
+
+// scanHashes - send the hashes (full or prefixes, per mode) of the safes to HMSL and rotate the
+// leaked ones
+func (b Brimstone) scanHashes(contextctx context.Context, mode string, safenames []string) (*ScanResult, error) {
+	start := time.Now()
+	defer func() {
+		metrics.ScanDuration.WithLabelValues(mode).Observe(time.Since(start).Seconds())
+	}()
 
 	// start gathering the response stats
-	stats := SendHashesStats{
-		Status:    0,
-		SendCount: 0,
-		SafeStats: &[]SafeHashStats{},
-		LeakStats: &[]hmsl.SecretResponse{},
+	result := ScanResult{
+		Stats: SendHashesStats{
+			Status:    0,
+			SendCount: 0,
+			SafeStats: &[]SafeHashStats{},
+			LeakStats: &[]hmsl.SecretResponse{},
+		},
+	}
+
+	if len(safenames) == 0 {
+		var err error
+		safenames, err = b.FetchSafenames()
+		if err != nil {
+			return &result, err
+		}
 	}
-	batchsize := 1000
 
 	// send batches of hashes to HMSL
-	// TODO: (low priority) use context.Context to store stats
-	var hashesleaked []hmsl.SecretResponse
-	var hashesleakedvalidationerrors []error
 	for i := 0; i < len(safenames); i++ {
-
-		hashvals, err := b.FetchHashes(safenames[i].Safename, &stats)
+		var secretresponses []hmsl.SecretResponse
+		var validationerrs []error
+		var err error
+		if mode == SCAN_MODE_PREFIX {
+			secretresponses, err = b.SendSafePrefixes(contextctx, safenames[i], &result.Stats)
+		} else {
+			secretresponses, validationerrs, err = b.SendSafeFullHashes(contextctx, safenames[i], &result.Stats)
+		}
 		if err != nil {
-			return err
-		}
-
-		// send hashes batch size limit to 1000; hmsl restriction.
-		if stats.SendCount > 0 {
-			var batch []string
-			for i := 0; i < len((*hashvals)); i += batchsize {
-				hashbody := hmsl.BatchHashesV1HashesPostJSONRequestBody{}
-				batch = (*hashvals)[i:utils.MinInt(i+batchsize, len((*hashvals)))]
-				fmt.Println(batch)
-
-				hashbody.Hashes = &batch
-				respHashes, respHashesErr := hmslclient.BatchHashesV1HashesPostWithResponse(contextctx, hashbody)
-				if respHashesErr != nil {
-					return respHashesErr
-				}
-
-				secretresponses, validationerrs := HandleHasheBatchResponses(batch, respHashes)
-				if len(validationerrs) > 0 {
-					hashesleakedvalidationerrors = append(hashesleakedvalidationerrors, validationerrs...)
-				}
-				hashesleaked = append(hashesleaked, secretresponses...)
-			}
+			return &result, err
 		}
+		result.SendCount += result.Stats.SendCount
+		result.ValidationErrors = append(result.ValidationErrors, validationerrs...)
+		result.Responses = append(result.Responses, secretresponses...)
 	}
 
-	for i := 0; i < len(hashesleaked); i++ {
-		err := b.ChangePasswordFromHash(ctx, hashesleaked[i].Hash)
-		msg := "succeded"
+	for i := 0; i < len(result.Responses); i++ {
+		if b.DryRun {
+			planned, err := b.PlanLeakedHash(&result.Responses[i])
+			if err != nil {
+				result.FailedCount++
+			}
+			result.Planned = append(result.Planned, planned...)
+			continue
+		}
+		err := b.RotateLeakedHash(contextctx, &result.Responses[i])
 		if err != nil {
-			msg = fmt.Sprintf("failed with error: %s", err.Error())
+			result.FailedCount++
+		} else {
+			result.RotatedCount++
 		}
-		if hashesleaked[i].Location == nil {
-			hashesleaked[i].Location = &hmsl.APILocation{
-				U: "Location=UNK",
+	}
+	*result.Stats.LeakStats = result.Responses
+
+	return &result, nil
+}
+
+// SendSafeFullHashes - send the full hmsl hashes of a safe to HMSL and return the leaked ones
+func (b Brimstone) SendSafeFullHashes(contextctx context.Context, safename string, stats *SendHashesStats) ([]hmsl.SecretResponse, []error, error) {
+	hmslclient := b.HMSLClient
+	batchsize := 1000
+
+	var hashesleaked []hmsl.SecretResponse
+	var hashesleakedvalidationerrors []error
+
+	hashvals, err := b.FetchHashes(safename, stats)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// send hashes batch size limit to 1000; hmsl restriction.
+	if stats.SendCount > 0 {
+		var batch []string
+		for i := 0; i < len((*hashvals)); i += batchsize {
+			hashbody := hmsl.BatchHashesV1HashesPostJSONRequestBody{}
+			batch = (*hashvals)[i:utils.MinInt(i+batchsize, len((*hashvals)))]
+			fmt.Println(batch)
+
+			hashbody.Hashes = &batch
+			respHashes, respHashesErr := hmslclient.BatchHashesV1HashesPostWithResponse(contextctx, hashbody)
+			if respHashesErr != nil {
+				return hashesleaked, hashesleakedvalidationerrors, respHashesErr
+			}
+
+			secretresponses, validationerrs := HandleHasheBatchResponses(batch, respHashes)
+			if len(validationerrs) > 0 {
+				hashesleakedvalidationerrors = append(hashesleakedvalidationerrors, validationerrs...)
 			}
+			hashesleaked = append(hashesleaked, secretresponses...)
 		}
-		hashesleaked[i].Location.U = fmt.Sprintf("Password request %s; Location=%s", msg, hashesleaked[i].Location.U)
 	}
+	metrics.LeaksFound.WithLabelValues(safename, SCAN_MODE_FULL).Add(float64(len(hashesleaked)))
 
-	var rsp = []struct {
-		Responses        []hmsl.SecretResponse
-		ValidationErrors []error
-	}{
-		{
-			Responses:        hashesleaked,
-			ValidationErrors: hashesleakedvalidationerrors,
-		},
+	return hashesleaked, hashesleakedvalidationerrors, nil
+}
+
+// RotateLeakedHash - change the password of the accounts with the leaked hash and
+// prefix the leak location with the outcome of the password change
+func (b Brimstone) RotateLeakedHash(contextctx context.Context, leaked *hmsl.SecretResponse) error {
+	err := b.ChangePasswordFromHash(contextctx, leaked.Hash)
+	msg := "succeded"
+	if err != nil {
+		msg = fmt.Sprintf("failed with error: %s", err.Error())
 	}
-	err := ctx.JSON(200, rsp)
+	if leaked.Location == nil {
+		leaked.Location = &hmsl.APILocation{
+			U: "Location=UNK",
+		}
+	}
+	leaked.Location.U = fmt.Sprintf("Password request %s; Location=%s", msg, leaked.Location.U)
 	return err
 }
 
+// PlanLeakedHash - dry-run counterpart of RotateLeakedHash; return the accounts whose password
+// would be changed and prefix the leak location with the planned password change
+func (b Brimstone) PlanLeakedHash(leaked *hmsl.SecretResponse) ([]AccountMetadata, error) {
+	var planned []AccountMetadata
+	accounts, err := b.FindAccounts(leaked.Hash)
+	for i := 0; i < len(accounts); i++ {
+		log.Printf("DRY RUN: would change password for acct id, %s, safename: %s\n", accounts[i].Name, accounts[i].Safename)
+		planned = append(planned, AccountMetadata{
+			Name:     accounts[i].Name,
+			SafeName: accounts[i].Safename,
+			Present:  true,
+			Action:   AUDIT_ACTION_ROTATE,
+			DryRun:   true,
+		})
+	}
+
+	msg := "planned (dry run)"
+	if err != nil {
+		msg = fmt.Sprintf("failed with error: %s", err.Error())
+	}
+	if leaked.Location == nil {
+		leaked.Location = &hmsl.APILocation{
+			U: "Location=UNK",
+		}
+	}
+	leaked.Location.U = fmt.Sprintf("Password request %s; Location=%s", msg, leaked.Location.U)
+	return planned, err
+}
+
 // GitGuardianEventPost - POST /v1/notify/ggevent
-func (b Brimstone) GitGuardianEventPost(ctx echo.Context) error {
-	// If we get here, then the GG header has already been validated
-	pamconfig := b.PAMConfig
-	client := pam.NewClient(pamconfig.PCloudURL, *pamconfig)
+func (b Brimstone) GitGuardianEventPost(ctx echo.Context, params GitGuardianEventPostParams) error {
+	b = b.withDryRun(params.DryRun)
 
+	// If we get here, then the GG header has already been validated
 	var event gg.IncidentEvent
 	err := ctx.Bind(&event)
 	if err != nil {
 		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for Incident Event")
 	}
+	metrics.WebhookEvents.WithLabelValues(event.Action).Inc()
+
+	audit := incidentAudit(AUDIT_SOURCE_GG_WEBHOOK, event)
+
+	// GG retries and replayed requests carry the same signature; acknowledge them without remediating again
+	delivery, claimed, err := b.ClaimWebhookDelivery(ctx.Request().Header.Get(gg.SIGNATURE_HEADER), audit.IncidentID)
+	if err != nil {
+		log.Printf("ERROR: unable to record webhook delivery for incident, %d: %s\n", audit.IncidentID, err.Error())
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to record webhook delivery")
+	}
+	if !claimed {
+		log.Printf("INFO: duplicate webhook delivery for incident, %d, ignored\n", audit.IncidentID)
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.ResponseCode = http.StatusOK
+		audit.Error = "duplicate webhook delivery"
+		b.RecordRemediation(audit)
+		return ctx.JSON(200, "duplicate")
+	}
+
+	if event.Incident.Id == nil {
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.ResponseCode = http.StatusBadRequest
+		audit.Error = "incident id not sent"
+		b.RecordRemediation(audit)
+		return sendBrimstoneError(ctx, http.StatusBadRequest, "Incident id not sent")
+	}
+
+	// every incident action updates the incident state; the configured behavior tells whether to remediate
+	incident, err := b.SaveIncident(event)
+	if err != nil {
+		log.Printf("ERROR: unable to save incident, %d: %s\n", audit.IncidentID, err.Error())
+		b.ReleaseWebhookDelivery(delivery)
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to save incident")
+	}
+	switch b.IncidentBehavior(event.Action) {
+	case INCIDENT_ACTION_CLOSE:
+		log.Printf("INFO: incident, %d, %s, remediation closed\n", audit.IncidentID, event.Action)
+		return ctx.JSON(200, REMEDIATION_STATUS_CLOSED)
+	case INCIDENT_ACTION_RECORD:
+		log.Printf("INFO: incident, %d, %s, recorded\n", audit.IncidentID, event.Action)
+		return ctx.JSON(200, "recorded")
+	}
 
-	if event.Action != "incident_triggered" && event.Action != "new_occurrence" {
-		return sendBrimstoneError(ctx, http.StatusBadRequest, "Incident already recorded")
+	accountsMetadata, code, err := b.RemediateIncident(ctx.Request().Context(), event, incident, audit)
+	if err != nil {
+		b.ReleaseWebhookDelivery(delivery)
+		return sendBrimstoneError(ctx, code, err.Error())
 	}
+	// return information about the account affected by the event
+	return ctx.JSON(200, accountsMetadata)
+}
+
+// RemediateIncident rotates the accounts matching the incident hmsl hash, or adds an account to the
+// pending safe when there is no match; on failure returns the http status and message to report
+func (b Brimstone) RemediateIncident(ctx context.Context, event gg.IncidentEvent, incident Incident, audit RemediationEvent) ([]AccountMetadata, int, error) {
+	pamconfig := b.PAMSessions.Config()
+	client := b.PAMSessions.Client()
 
 	// hmsl_hash only exists on the Incident
 	if event.Incident.HmslHash == nil {
-		return sendBrimstoneError(ctx, http.StatusNotFound, "HMSL hash not sent as a parameter")
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.ResponseCode = http.StatusNotFound
+		audit.Error = "hmsl hash not sent"
+		b.RecordRemediation(audit)
+		return nil, http.StatusNotFound, fmt.Errorf("HMSL hash not sent as a parameter")
 	}
 
 	accounts, err := b.FindAccounts(*event.Incident.HmslHash)
 	if err != nil {
 		log.Printf("Error finding hmsl hash: %s\n", err.Error())
-		return sendBrimstoneError(ctx, http.StatusNotFound, "No matching hmsl hash")
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.ResponseCode = http.StatusNotFound
+		audit.Error = err.Error()
+		b.RecordRemediation(audit)
+		return nil, http.StatusNotFound, fmt.Errorf("No matching hmsl hash")
 	}
-	err = client.RefreshSessionToken()
+	_, err = b.PAMSessions.Session(ctx)
 	if err != nil {
 		log.Printf("Error refreshing PAM session token: %s\n", err.Error())
-		return sendBrimstoneError(ctx, http.StatusBadGateway, "Unable to obtain PAM session token")
+		return nil, http.StatusBadGateway, fmt.Errorf("Unable to obtain PAM session token")
 	}
 
 	// Found a matching HMSL Hash, so, let's tell PAM to change the password
 	var accountsMetadata []AccountMetadata
+	var remediated []RemediationEvent
 	if len(accounts) > 0 {
 		for i := 0; i < len(accounts); i++ {
 			var accountMetadata AccountMetadata
@@ -366,14 +603,28 @@ func (b Brimstone) GitGuardianEventPost(ctx echo.Context) error {
 			log.Printf("Account ID: %s\n", accounts[i].Name)
 			accountMetadata.Name = accounts[i].Name
 			accountMetadata.SafeName = accounts[i].Safename
-			_, err := client.ChangePasswordImmediately(accounts[i].Name)
+			accountMetadata.Action = AUDIT_ACTION_ROTATE
+			if b.DryRun {
+				log.Printf("DRY RUN: would change password for acct id, %s\n", accounts[i].Name)
+				accountMetadata.DryRun = true
+				accountsMetadata = append(accountsMetadata, accountMetadata)
+				continue
+			}
+			accountaudit := audit
+			accountaudit.Safename = accounts[i].Safename
+			accountaudit.AccountID = accounts[i].Name
+			accountaudit, err := b.RequestRotation(ctx, &client, accountaudit)
 			if err != nil {
 				log.Printf("ERROR: failed to change password for acct id, %s: %s\n", accounts[i].Name, err.Error())
+			} else if accountaudit.RotationStatus == ROTATION_PENDING {
+				accountMetadata.RotationStatus = ROTATION_PENDING
 			} else {
 				accountMetadata.Rotated = true
 			}
+			remediated = append(remediated, accountaudit)
 			accountsMetadata = append(accountsMetadata, accountMetadata)
 		}
+		b.LinkIncidentAccounts(incident, accounts)
 	} else {
 		// NO matching HMSL Hash, so, let's add a new account to PAM
 		acctname := "gitguardian"
@@ -393,13 +644,32 @@ func (b Brimstone) GitGuardianEventPost(ctx echo.Context) error {
 			SecretType:                "password",
 			PlatformAccountProperties: pam.PlatformAccountProperties{},
 		}
-		newaccount, code, err := client.AddAccount(addreq)
+		if b.DryRun {
+			log.Printf("DRY RUN: would add account, %s, to safename: %s\n", addreq.Name, addreq.SafeName)
+			accountsMetadata = append(accountsMetadata, AccountMetadata{
+				Name:     addreq.Name,
+				SafeName: addreq.SafeName,
+				Action:   AUDIT_ACTION_ADD_ACCOUNT,
+				DryRun:   true,
+			})
+			return accountsMetadata, http.StatusOK, nil
+		}
+		newaccount, code, err := client.AddAccount(ctx, addreq)
+		audit.Action = AUDIT_ACTION_ADD_ACCOUNT
+		audit.Safename = pamconfig.SafeName
+		audit.ResponseCode = code
+		audit.Error = errorString(err)
 		if err != nil || newaccount.ID == "" {
-			return sendBrimstoneError(ctx, code, "Unable to add PAM account from GG incident")
+			if audit.Error == "" {
+				audit.Error = "no account id returned"
+			}
+			b.RecordRemediation(audit)
+			return nil, code, fmt.Errorf("Unable to add PAM account from GG incident")
 		}
 		var accountMetadata AccountMetadata
 		accountMetadata.Name = newaccount.ID
 		accountMetadata.SafeName = newaccount.SafeName
+		accountMetadata.Action = AUDIT_ACTION_ADD_ACCOUNT
 
 		db := b.Db
 		var newsafehashes []SafeHash
@@ -412,20 +682,27 @@ func (b Brimstone) GitGuardianEventPost(ctx echo.Context) error {
 		result := db.CreateInBatches(newsafehashes, 1)
 		if result != nil && result.Error != nil {
 			log.Printf("unable to save new account hmsl hash (%s, %s, %s): %s\n", newhash.Safename, newhash.Name, newhash.Hash, result.Error.Error())
+			audit.Error = result.Error.Error()
 		} else {
 			accountMetadata.Added = true
+			b.LinkIncidentAccounts(incident, newsafehashes)
 		}
+		audit.Safename = newaccount.SafeName
+		audit.AccountID = newaccount.ID
+		b.RecordRemediation(audit)
+		remediated = append(remediated, audit)
 		accountsMetadata = append(accountsMetadata, accountMetadata)
 	}
+	b.MarkIncidentRemediated(incident, remediated)
+	b.WriteBackIncident(ctx, audit.IncidentID, remediated)
 	// return information about the account affected by the event
-	return ctx.JSON(200, accountsMetadata)
-
+	return accountsMetadata, http.StatusOK, nil
 }
 
 // CyberArkPAMCPMEventPut receive CPM plugin request; CPM updated the password, this request is telling brimstone to update its database
-func (b Brimstone) CyberArkPAMCPMEventPut(ctx echo.Context) error {
+func (b Brimstone) CyberArkPAMCPMEventPut(ctx echo.Context, params CyberArkPAMCPMEventPutParams) error {
+	b = b.withDryRun(params.DryRun)
 	db := b.Db
-	pamconfig := b.PAMConfig
 
 	var event HashBatch
 	err := ctx.Bind(&event)
@@ -438,21 +715,39 @@ func (b Brimstone) CyberArkPAMCPMEventPut(ctx echo.Context) error {
 	}
 
 	// CPM will usually send account name (not account id), so, we attempt to determine accountid by querying PAM
-	client := pam.NewClient(pamconfig.PCloudURL, *pamconfig)
-	err = client.RefreshSessionToken()
+	client := b.PAMSessions.Client()
+	_, err = b.PAMSessions.Session(ctx.Request().Context())
 	if err != nil {
 		log.Printf("Error refreshing PAM session token: %s\n", err.Error())
 		return sendBrimstoneError(ctx, http.StatusBadGateway, "Unable to obtain PAM session token")
 	}
 
 	// Loookup acount id based on account name
-	accountid, rescode, errFetchId := client.FetchAccountIdFromAccountName(event.Safename, event.Hashes[0].Name)
+	accountid, rescode, errFetchId := client.FetchAccountIdFromAccountName(ctx.Request().Context(), event.Safename, event.Hashes[0].Name)
+	audit := RemediationEvent{
+		Source:       AUDIT_SOURCE_CPM,
+		Safename:     event.Safename,
+		AccountID:    accountid,
+		HashPrefix:   HashPrefix(event.Hashes[0].Hash),
+		Action:       AUDIT_ACTION_UPDATE_HASH,
+		ResponseCode: rescode,
+		Error:        errorString(errFetchId),
+	}
 	if rescode == 404 {
 		log.Printf("unable to determine accountid, safename: %s, account name: %s\n", event.Safename, event.Hashes[0].Name)
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.AccountID = event.Hashes[0].Name
+		if audit.Error == "" {
+			audit.Error = "account not found"
+		}
+		b.RecordRemediation(audit)
 		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("unable to determine accountid, safename: %s, account name: %s", event.Safename, event.Hashes[0].Name))
 	}
 	if rescode > 299 && errFetchId != nil {
 		log.Printf("error with result code, %d: %s\n", rescode, errFetchId.Error())
+		audit.Action = AUDIT_ACTION_SKIP
+		audit.AccountID = event.Hashes[0].Name
+		b.RecordRemediation(audit)
 		return sendBrimstoneError(ctx, rescode, fmt.Sprintf("error with result code, %d: %s", rescode, errFetchId.Error()))
 	}
 	// Adjust the event obj to use account id instead of name
@@ -461,9 +756,23 @@ func (b Brimstone) CyberArkPAMCPMEventPut(ctx echo.Context) error {
 
 	var hashes []SafeHash
 	result := db.Limit(1).Where("safename = ? AND name = ?", event.Safename, event.Hashes[0].Name).Find(&hashes)
+	if b.DryRun {
+		log.Printf("DRY RUN: would save hash, safename: %s, account id: %s\n", event.Safename, event.Hashes[0].Name)
+		accountMetadata := AccountMetadata{
+			Name:     event.Hashes[0].Name,
+			SafeName: event.Safename,
+			Present:  result.RowsAffected != 0,
+			Action:   AUDIT_ACTION_UPDATE_HASH,
+			DryRun:   true,
+		}
+		return ctx.JSON(200, []AccountMetadata{accountMetadata})
+	}
 	if result.RowsAffected != 0 {
 		log.Printf("saving next version of hash, safename: %s, account id: %s, hash: %s\n", event.Safename, event.Hashes[0].Name, event.Hashes[0].Hash)
-		return b.SaveExistingSafeHashes(ctx, event)
+		err = b.SaveExistingSafeHashes(ctx, event)
+		audit.Error = errorString(err)
+		b.RecordRemediation(audit)
+		return err
 	}
 
 	// safe with new hash (CPM will only send 1 hash when an account password is reset)
@@ -476,6 +785,8 @@ func (b Brimstone) CyberArkPAMCPMEventPut(ctx echo.Context) error {
 	newsafehashes = append(newsafehashes, newhash)
 	log.Printf("saving new version of hash, safename: %s, account id: %s, hash: %s\n", event.Safename, event.Hashes[0].Name, event.Hashes[0].Hash)
 	result = db.CreateInBatches(newsafehashes, 10)
+	audit.Error = errorString(result.Error)
+	b.RecordRemediation(audit)
 
 	return result.Error
 }
@@ -491,6 +802,37 @@ func (b Brimstone) FindAccounts(hmslhash string) ([]SafeHash, error) {
 	return hashes, nil
 }
 
+// CountHashesBySafe - return the number of stored hash versions of each safe
+func (b Brimstone) CountHashesBySafe() (map[string]int, error) {
+	db := b.Db
+
+	var rows []struct {
+		Safename string
+		Count    int
+	}
+	result := db.Model(&SafeHash{}).Select("safename, count(*) as count").Group("safename").Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	counts := make(map[string]int)
+	for i := 0; i < len(rows); i++ {
+		counts[rows[i].Safename] = rows[i].Count
+	}
+	return counts, nil
+}
+
+// FetchSafenames - return the distinct list of safe names in the db
+func (b Brimstone) FetchSafenames() ([]string, error) {
+	db := b.Db
+
+	var safenames []string
+	result := db.Model(&SafeHash{}).Distinct("safename").Order("safename").Pluck("safename", &safenames)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return safenames, nil
+}
+
 // FetchHashes - given safename return list of hmsl hashes from the db associated to that safe
 func (b Brimstone) FetchHashes(safename string, stats *SendHashesStats) (*[]string, error) {
 	db := b.Db
@@ -516,7 +858,9 @@ func (b Brimstone) FetchHashes(safename string, stats *SendHashesStats) (*[]stri
 	slices.Sort(hashvals)
 	hashvals = slices.Compact(hashvals)
 	safestats.DistinctCount = len(hashvals)
-	stats.SendCount = len(hashvals) // number of valid prefixes to send
+	x := append(*stats.SafeStats, safestats)
+	stats.SafeStats = &x
+	stats.SendCount = len(hashvals) // number of valid hashes to send
 
 	return &hashvals, nil
 }
@@ -572,12 +916,24 @@ func (b Brimstone) FetchPrefixes(safename string, stats *SendHashesStats) (*[]st
 	return &validprefixes, nil
 }
 
+// FetchHashesByPrefix - given safename return the distinct hmsl hashes of the safe starting
+// with one of the prefixes
+func (b Brimstone) FetchHashesByPrefix(safename string, prefixes []string) ([]string, error) {
+	db := b.Db
+
+	var hashes []string
+	result := db.Model(&SafeHash{}).Distinct("hash").Where("safename = ? AND substr(hash, 1, 5) IN ?", safename, prefixes).Pluck("hash", &hashes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return hashes, nil
+}
+
 // ChangePasswordFromHash - given hmslhash lookup accountid and call to pam api to change password
-func (b Brimstone) ChangePasswordFromHash(ctx echo.Context, hmslhash string) error {
-	pamconfig := b.PAMConfig
-	client := pam.NewClient(pamconfig.PCloudURL, *pamconfig)
+func (b Brimstone) ChangePasswordFromHash(ctx context.Context, hmslhash string) error {
+	client := b.PAMSessions.Client()
 
-	clientErr := client.RefreshSessionToken()
+	_, clientErr := b.PAMSessions.Session(ctx)
 	if clientErr != nil {
 		return fmt.Errorf("error refreshing PAM session token: %s", clientErr.Error())
 	}
@@ -586,9 +942,18 @@ func (b Brimstone) ChangePasswordFromHash(ctx echo.Context, hmslhash string) err
 	if len(accounts) > 0 {
 		for i := 0; i < len(accounts); i++ {
 			log.Printf("Account ID: %s\n", accounts[i].Name)
-			code, err := client.ChangePasswordImmediately(accounts[i].Name)
+			if b.DryRun {
+				log.Printf("DRY RUN: would change password for acct id, %s\n", accounts[i].Name)
+				continue
+			}
+			rotationaudit, err := b.RequestRotation(ctx, &client, RemediationEvent{
+				Source:     AUDIT_SOURCE_HMSL_SCAN,
+				HashPrefix: HashPrefix(hmslhash),
+				Safename:   accounts[i].Safename,
+				AccountID:  accounts[i].Name,
+			})
 			if err != nil {
-				return fmt.Errorf("failed to change password for acct id, %s: (code=%d) %s", accounts[i].Name, code, err.Error())
+				return fmt.Errorf("failed to change password for acct id, %s: (code=%d) %s", accounts[i].Name, rotationaudit.ResponseCode, err.Error())
 			}
 		}
 	}
@@ -599,36 +964,75 @@ func (b Brimstone) ChangePasswordFromHash(ctx echo.Context, hmslhash string) err
 	return nil
 }
 
-// HandlePrefixes - TODO: (low priority) add vault remediation logic for prefix responses
-func HandlePrefixes(hashes []string, responses *hmsl.BatchPrefixesV1PrefixesPostResponse) *hmsl.PrefixesResponse {
-	var result hmsl.PrefixesResponse
-	hints := make(map[string]string)
+// HandlePrefixes - HMSL returns the encrypted secrets of every leaked hash sharing the prefixes;
+// a match is one of ours when its hint is the hint of one of our full hashes, which is also
+// the key to decrypt its payload
+func HandlePrefixes(hashes []string, responses *hmsl.BatchPrefixesV1PrefixesPostResponse) ([]hmsl.SecretResponse, []error) {
+	var hashesleaked []hmsl.SecretResponse
+	var validationerrors []error
 
+	if responses == nil {
+		e := fmt.Errorf("no responses to process")
+		validationerrors = append(validationerrors, e)
+		return hashesleaked, validationerrors
+	}
+
+	hints := make(map[string]string)
 	for i := 0; i < len(hashes); i++ {
 		h, e := hmsl.ComputeHint(hashes[i])
-		if e == nil {
-			hints[h] = hashes[i]
+		if e != nil {
+			validationerrors = append(validationerrors, fmt.Errorf("unable to compute hint for hash, %s: %s", HashPrefix(hashes[i]), e.Error()))
 			continue
 		}
+		hints[h] = hashes[i]
 	}
 
 	// foreach of the batch items: find which ones have matches from the responses
-	for i := 0; i < len(responses.JSON200.Matches); i++ {
+	if responses.JSON200 != nil {
+		for i := 0; i < len(responses.JSON200.Matches); i++ {
+			match := responses.JSON200.Matches[i]
+			hash, ok := hints[match.Hint]
+			if !ok {
+				// someone else's secret sharing the prefix
+				continue
+			}
 
-		pbytes, pbErr := responses.JSON200.Matches[i].Payload.Bytes()
-		if pbErr != nil {
-			log.Printf("Error converting to bytes: %s\n", pbErr.Error())
-		}
+			pbytes, pbErr := match.Payload.Bytes()
+			if pbErr != nil {
+				validationerrors = append(validationerrors, fmt.Errorf("unable to read payload for hash, %s: %s", HashPrefix(hash), pbErr.Error()))
+				continue
+			}
 
-		msg1, err1 := hmsl.DecryptPayload(pbytes, hints[responses.JSON200.Matches[i].Hint])
-		if err1 != nil {
-			log.Printf("Error decrypt payload failed with hmslhash: %s\n", err1.Error())
-		}
+			msg, err := hmsl.DecryptPayload(pbytes, hash)
+			if err != nil {
+				validationerrors = append(validationerrors, fmt.Errorf("unable to decrypt payload for hash, %s: %s", HashPrefix(hash), err.Error()))
+				continue
+			}
 
-		log.Printf("HMSLHASH: %s\n", msg1)
-	}
+			var secret hmsl.SecretResponse
+			err = json.Unmarshal([]byte(msg), &secret)
+			if err != nil {
+				validationerrors = append(validationerrors, fmt.Errorf("invalid payload for hash, %s: %s", HashPrefix(hash), err.Error()))
+				continue
+			}
+			secret.Hash = hash
 
-	return &result
+			loc := "UNK"
+			if secret.Location != nil {
+				loc = secret.Location.U
+			}
+			log.Printf("INFO: Secret Result, count=%d, hash=%s, location=%s\n", secret.Count, secret.Hash, loc)
+			hashesleaked = append(hashesleaked, secret)
+		}
+	}
+	if responses.JSON422 != nil && responses.JSON422.Detail != nil {
+		for i := 0; i < len(*responses.JSON422.Detail); i++ {
+			detail := (*responses.JSON422.Detail)[i]
+			m := fmt.Errorf("422 validation error (%s) %s", detail.Type, detail.Msg)
+			validationerrors = append(validationerrors, m)
+		}
+	}
+	return hashesleaked, validationerrors
 }
 
 // HandleHasheBatchResponses
@@ -0,0 +1,115 @@
+package brimstone
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDryRun(t *testing.T) {
+	on := true
+	off := false
+
+	b := Brimstone{}
+	assert.False(t, b.withDryRun(nil).DryRun)
+	assert.False(t, b.withDryRun(&off).DryRun)
+	assert.True(t, b.withDryRun(&on).DryRun)
+	assert.False(t, b.DryRun)
+
+	// server wide dry-run cannot be turned off by a request
+	b.DryRun = true
+	assert.True(t, b.withDryRun(&off).DryRun)
+}
+
+func TestPlanLeakedHash(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.DryRun = true
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: "aaaa1"},
+		{Safename: "safe2", Name: "2_1", Hash: "aaaa1"},
+		{Safename: "safe2", Name: "2_2", Hash: "bbbb2"},
+	})
+
+	leaked := hmsl.SecretResponse{Hash: "aaaa1"}
+	planned, err := b.PlanLeakedHash(&leaked)
+	assert.NoError(t, err)
+	assert.Len(t, planned, 2)
+	for i := 0; i < len(planned); i++ {
+		assert.Equal(t, AUDIT_ACTION_ROTATE, planned[i].Action)
+		assert.True(t, planned[i].DryRun)
+		assert.True(t, planned[i].Present)
+	}
+	assert.Contains(t, leaked.Location.U, "planned (dry run)")
+
+	// dry runs are not recorded in the audit trail
+	b.RecordRemediation(RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Action: AUDIT_ACTION_ROTATE})
+	var count int64
+	b.Db.Model(&RemediationEvent{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+// encryptSecret builds an HMSL prefix match for the hmsl hash the way HMSL does
+func encryptSecret(t *testing.T, hmslhash string, secret hmsl.SecretResponse) (string, string) {
+	key, err := hex.DecodeString(hmslhash)
+	assert.NoError(t, err)
+	hint := sha256.Sum256(key)
+
+	block, err := aes.NewCipher(key)
+	assert.NoError(t, err)
+	aesgcm, err := cipher.NewGCM(block)
+	assert.NoError(t, err)
+	cleartext, err := json.Marshal(secret)
+	assert.NoError(t, err)
+	nonce := make([]byte, 12)
+	payload := aesgcm.Seal(nonce, nonce, cleartext, nil)
+
+	return fmt.Sprintf("%x", hint), base64.StdEncoding.EncodeToString(payload)
+}
+
+func TestScanPrefixesDryRun(t *testing.T) {
+	leakedhash := "aaaa1" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789a"
+	safehash := "aaaa1" + "fedcba9876543210fedcba9876543210fedcba9876543210fedcba98765"
+	otherhash := "aaaa1" + "00000000000000000000000000000000000000000000000000000000000"
+
+	var prefixes []string
+	hmslstub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query hmsl.PrefixesQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		prefixes = append(prefixes, *query.Prefixes...)
+
+		// one of ours and someone else's secret sharing the prefix
+		hint1, payload1 := encryptSecret(t, leakedhash, hmsl.SecretResponse{Count: 2, Location: &hmsl.APILocation{U: "https://example.com/leak"}})
+		hint2, payload2 := encryptSecret(t, otherhash, hmsl.SecretResponse{Count: 1})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"matches": [{"hint": "%s", "payload": "%s"}, {"hint": "%s", "payload": "%s"}]}`, hint1, payload1, hint2, payload2)
+	}))
+	defer hmslstub.Close()
+
+	b := newTestBrimstone(t, hmslstub.URL)
+	b.DryRun = true
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: leakedhash},
+		{Safename: "safe1", Name: "1_2", Hash: safehash},
+	})
+
+	result, err := b.ScanPrefixes(context.Background(), []string{"safe1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aaaa1"}, prefixes)
+	assert.Len(t, result.Responses, 1)
+	assert.Equal(t, leakedhash, result.Responses[0].Hash)
+	assert.Equal(t, 2, result.Responses[0].Count)
+	assert.Contains(t, result.Responses[0].Location.U, "https://example.com/leak")
+	assert.Len(t, result.Planned, 1)
+	assert.Equal(t, "1_1", result.Planned[0].Name)
+}
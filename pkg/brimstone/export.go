@@ -0,0 +1,199 @@
+package brimstone
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// version of the hash export format
+const HASH_EXPORT_VERSION = 1
+
+// max size of an imported hash export
+const MAX_HASH_EXPORT_SIZE = 256 << 20
+
+// ErrExportSignature - the hash export was not signed with the export key, or was modified
+var ErrExportSignature = errors.New("hash export signature not verified")
+
+// HashExportHeader - first line of a hash export
+type HashExportHeader struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Batches   int       `json:"batches"`
+}
+
+// HashExportSignature - last line of a hash export, the hex HMAC-SHA256 of every line before it
+type HashExportSignature struct {
+	Signature string `json:"signature"`
+}
+
+// SafeImport - creation time of the last hash export imported for the safe; an export that is
+// not newer is a replay, its hashes would be saved as the newest versions of the accounts
+type SafeImport struct {
+	gorm.Model
+	Safename        string `gorm:"uniqueIndex"`
+	ExportCreatedAt time.Time
+}
+
+// HashExport - verified content of a hash export
+type HashExport struct {
+	HashExportHeader
+	Batches []HashBatch
+}
+
+func signExport(content []byte, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WriteHashExport writes the hash batches as JSONL, for vaults that cannot reach brimstone: a
+// header line, one line per batch, then the signature line, signed with the shared export key
+func WriteHashExport(w io.Writer, batches []HashBatch, key []byte, now time.Time) error {
+	if len(key) == 0 {
+		return errors.New("hash export key is required")
+	}
+	var content bytes.Buffer
+	enc := json.NewEncoder(&content)
+	if err := enc.Encode(HashExportHeader{Version: HASH_EXPORT_VERSION, CreatedAt: now.UTC(), Batches: len(batches)}); err != nil {
+		return err
+	}
+	for i := 0; i < len(batches); i++ {
+		if err := enc.Encode(batches[i]); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(HashExportSignature{Signature: signExport(content.Bytes(), key)}); err != nil {
+		return err
+	}
+	_, err := w.Write(content.Bytes())
+	return err
+}
+
+// ReadHashExport reads a hash export, verifying its signature with the shared export key
+// before any batch is returned
+func ReadHashExport(r io.Reader, key []byte) (HashExport, error) {
+	var export HashExport
+	if len(key) == 0 {
+		return export, errors.New("hash export key is required")
+	}
+
+	var lines [][]byte
+	var signed []byte
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return export, err
+		}
+	}
+	if len(lines) < 2 {
+		return export, errors.New("invalid hash export, expected a header and a signature line")
+	}
+
+	var signature HashExportSignature
+	last := lines[len(lines)-1]
+	if err := json.Unmarshal(last, &signature); err != nil || signature.Signature == "" {
+		return export, errors.New("invalid hash export, the last line is not the signature")
+	}
+	for i := 0; i < len(lines)-1; i++ {
+		signed = append(signed, lines[i]...)
+	}
+	if !hmac.Equal([]byte(signature.Signature), []byte(signExport(signed, key))) {
+		return export, ErrExportSignature
+	}
+
+	if err := json.Unmarshal(lines[0], &export.HashExportHeader); err != nil {
+		return export, fmt.Errorf("invalid hash export header: %s", err.Error())
+	}
+	if export.Version != HASH_EXPORT_VERSION {
+		return export, fmt.Errorf("unsupported hash export version, %d", export.Version)
+	}
+	for i := 1; i < len(lines)-1; i++ {
+		var batch HashBatch
+		if err := json.Unmarshal(lines[i], &batch); err != nil {
+			return export, fmt.Errorf("invalid hash batch, line %d: %s", i+1, err.Error())
+		}
+		if batch.Safename == "" {
+			return export, fmt.Errorf("invalid hash batch, line %d: missing safename", i+1)
+		}
+		export.Batches = append(export.Batches, batch)
+	}
+	if len(export.Batches) != export.HashExportHeader.Batches {
+		return export, fmt.Errorf("invalid hash export, %d batch(es), header says %d", len(export.Batches), export.HashExportHeader.Batches)
+	}
+	return export, nil
+}
+
+// HashesImportPost - POST /v1/hashes/import
+func (b Brimstone) HashesImportPost(ctx echo.Context) error {
+	if len(b.HashExportKey) == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotImplemented, "Hash import requires HASH_EXPORT_KEY")
+	}
+
+	export, err := ReadHashExport(io.LimitReader(ctx.Request().Body, MAX_HASH_EXPORT_SIZE), b.HashExportKey)
+	if err != nil {
+		log.Printf("ERROR: hash import rejected: %s\n", err.Error())
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	// postgres keeps microseconds, the replay of an export must compare equal
+	createdat := export.CreatedAt.Truncate(time.Microsecond)
+	imports := map[string]SafeImport{}
+	for i := 0; i < len(export.Batches); i++ {
+		safename := export.Batches[i].Safename
+		if _, ok := imports[safename]; ok {
+			continue
+		}
+		var last SafeImport
+		result := b.Db.Where("safename = ?", safename).Limit(1).Find(&last)
+		if result.Error != nil {
+			return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch the last hash imports")
+		}
+		if last.ID != 0 && !createdat.After(last.ExportCreatedAt) {
+			log.Printf("ERROR: hash import rejected, export created at %s, safe, %s, imported an export created at %s\n", export.CreatedAt.Format(time.RFC3339Nano), safename, last.ExportCreatedAt.Format(time.RFC3339Nano))
+			return sendBrimstoneError(ctx, http.StatusConflict, fmt.Sprintf("hash export is not newer than the last import of safe, %s", safename))
+		}
+		imports[safename] = last
+	}
+
+	rsp := ImportResult{CreatedAt: export.CreatedAt}
+	for i := 0; i < len(export.Batches); i++ {
+		err = b.SaveHashBatch(export.Batches[i])
+		if err != nil {
+			log.Printf("ERROR: unable to import hashes of safe, %s: %s\n", export.Batches[i].Safename, err.Error())
+			return sendBrimstoneError(ctx, http.StatusInternalServerError, fmt.Sprintf("Unable to import hashes of safe, %s", export.Batches[i].Safename))
+		}
+		// recorded as soon as the safe is saved, a failure of a later safe does not allow a replay
+		last := imports[export.Batches[i].Safename]
+		last.Safename = export.Batches[i].Safename
+		last.ExportCreatedAt = createdat
+		result := b.Db.Save(&last)
+		if result.Error != nil {
+			log.Printf("ERROR: unable to save the last hash import of safe, %s: %s\n", last.Safename, result.Error.Error())
+		}
+		imports[last.Safename] = last
+		rsp.Safes++
+		rsp.Hashes += len(export.Batches[i].Hashes)
+	}
+	log.Printf("INFO: imported hash export created at %s, safes: %d, hashes: %d\n", export.CreatedAt.Format(time.RFC3339), rsp.Safes, rsp.Hashes)
+	return ctx.JSON(200, rsp)
+}
@@ -0,0 +1,124 @@
+package brimstone
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var exportBatches = []HashBatch{
+	{Safename: "safe1", Hashes: []Hash{{Name: "1_1", Hash: "aaaa1111"}, {Name: "1_2", Hash: "bbbb2222"}}},
+	{Safename: "safe2", Hashes: []Hash{{Name: "2_1", Hash: "cccc3333"}}},
+}
+
+func TestHashExportRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteHashExport(&buf, exportBatches, []byte("s3cret"), created))
+
+	// header, one line per batch, signature
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[3], `"signature"`)
+
+	export, err := ReadHashExport(bytes.NewReader(buf.Bytes()), []byte("s3cret"))
+	assert.NoError(t, err)
+	assert.Equal(t, created, export.CreatedAt)
+	assert.Equal(t, exportBatches, export.Batches)
+
+	_, err = ReadHashExport(bytes.NewReader(buf.Bytes()), []byte("other"))
+	assert.ErrorIs(t, err, ErrExportSignature)
+}
+
+func TestHashExportTampered(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteHashExport(&buf, exportBatches, []byte("s3cret"), time.Now()))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	// a modified hash
+	tampered := strings.Replace(buf.String(), "aaaa1111", "ffff1111", 1)
+	_, err := ReadHashExport(strings.NewReader(tampered), []byte("s3cret"))
+	assert.ErrorIs(t, err, ErrExportSignature)
+
+	// a removed batch
+	removed := strings.Join([]string{lines[0], lines[1], lines[3]}, "\n")
+	_, err = ReadHashExport(strings.NewReader(removed), []byte("s3cret"))
+	assert.ErrorIs(t, err, ErrExportSignature)
+
+	// no signature
+	unsigned := strings.Join(lines[:3], "\n")
+	_, err = ReadHashExport(strings.NewReader(unsigned), []byte("s3cret"))
+	assert.ErrorContains(t, err, "signature")
+}
+
+func TestHashesImportPost(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.Db.Create(&SafeHash{Safename: "safe1", Name: "1_1", Hash: "0000aaaa"})
+	e := echo.New()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteHashExport(&buf, exportBatches, []byte("s3cret"), time.Now()))
+	export := buf.Bytes()
+
+	// disabled without the export key
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/hashes/import", bytes.NewReader(export)), rec)
+	assert.NoError(t, b.HashesImportPost(ctx))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	b.HashExportKey = []byte("other")
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/hashes/import", bytes.NewReader(export)), rec)
+	assert.NoError(t, b.HashesImportPost(ctx))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var count int64
+	b.Db.Model(&SafeHash{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	b.HashExportKey = []byte("s3cret")
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/hashes/import", bytes.NewReader(export)), rec)
+	assert.NoError(t, b.HashesImportPost(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rsp ImportResult
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	assert.Equal(t, 2, rsp.Safes)
+	assert.Equal(t, 3, rsp.Hashes)
+
+	// the existing safe gets the next version of 1_1, the new safe its hashes
+	var hashes []SafeHash
+	b.Db.Where("name = ?", "1_1").Order("id").Find(&hashes)
+	assert.Len(t, hashes, 2)
+	assert.Equal(t, "aaaa1111", hashes[1].Hash)
+	b.Db.Model(&SafeHash{}).Count(&count)
+	assert.Equal(t, int64(4), count)
+
+	// a replayed export, or an older one, is rejected
+	var older bytes.Buffer
+	assert.NoError(t, WriteHashExport(&older, exportBatches, []byte("s3cret"), time.Now().Add(-time.Hour)))
+	for _, replay := range [][]byte{export, older.Bytes()} {
+		rec = httptest.NewRecorder()
+		ctx = e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/hashes/import", bytes.NewReader(replay)), rec)
+		assert.NoError(t, b.HashesImportPost(ctx))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "not newer than the last import")
+	}
+	b.Db.Model(&SafeHash{}).Count(&count)
+	assert.Equal(t, int64(4), count)
+
+	// a newer export is imported
+	var newer bytes.Buffer
+	assert.NoError(t, WriteHashExport(&newer, exportBatches[:1], []byte("s3cret"), time.Now().Add(time.Minute)))
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodPost, "/v1/hashes/import", bytes.NewReader(newer.Bytes())), rec)
+	assert.NoError(t, b.HashesImportPost(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,174 @@
+package brimstone
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	"gorm.io/gorm"
+)
+
+// What brimstone does when it receives a GG incident action
+const (
+	INCIDENT_ACTION_REMEDIATE = "remediate" // rotate the matching accounts, or add an account when there is no match
+	INCIDENT_ACTION_CLOSE     = "close"     // mark the remediation closed
+	INCIDENT_ACTION_RECORD    = "record"    // only update the incident state
+)
+
+// Remediation status of an incident
+const (
+	REMEDIATION_STATUS_OPEN   = "open"
+	REMEDIATION_STATUS_CLOSED = "closed"
+)
+
+// DEFAULT_INCIDENT_ACTIONS - GG incident actions handled by default, any other action is recorded
+const DEFAULT_INCIDENT_ACTIONS = "incident_triggered:remediate,new_occurrence:remediate,incident_reopened:remediate,incident_resolved:close,incident_ignored:close"
+
+// Incident - state of a GG incident, linked to the accounts whose hash it matched
+type Incident struct {
+	gorm.Model
+	IncidentID        int `gorm:"uniqueIndex"`
+	LastAction        string
+	Status            string
+	Severity          string
+	GitguardianURL    string
+	HashPrefix        string
+	RemediationStatus string
+	RemediatedAt      *time.Time
+	Accounts          []SafeHash `gorm:"many2many:incident_accounts;"`
+}
+
+// ParseIncidentActions parses a comma separated list of action:behavior pairs,
+// ex: incident_reopened:remediate,incident_resolved:close
+func ParseIncidentActions(s string) (map[string]string, error) {
+	actions := map[string]string{}
+	pairs := strings.Split(s, ",")
+	for i := 0; i < len(pairs); i++ {
+		pair := strings.TrimSpace(pairs[i])
+		if pair == "" {
+			continue
+		}
+		action, behavior, found := strings.Cut(pair, ":")
+		if !found || action == "" {
+			return nil, fmt.Errorf("invalid incident action, %s, expected action:behavior", pair)
+		}
+		switch behavior {
+		case INCIDENT_ACTION_REMEDIATE, INCIDENT_ACTION_CLOSE, INCIDENT_ACTION_RECORD:
+			actions[action] = behavior
+		default:
+			return nil, fmt.Errorf("invalid behavior, %s, for incident action, %s", behavior, action)
+		}
+	}
+	return actions, nil
+}
+
+// IncidentBehavior - what to do for the GG incident action, per the configured incident actions
+func (b Brimstone) IncidentBehavior(action string) string {
+	actions := b.IncidentActions
+	if actions == nil {
+		actions, _ = ParseIncidentActions(DEFAULT_INCIDENT_ACTIONS)
+	}
+	if behavior, ok := actions[action]; ok {
+		return behavior
+	}
+	return INCIDENT_ACTION_RECORD
+}
+
+// SaveIncident creates or updates the incident from the GG event. In dry-run mode the
+// stored incident is returned unchanged.
+func (b Brimstone) SaveIncident(event gg.IncidentEvent) (Incident, error) {
+	db := b.Db
+	var incident Incident
+	if event.Incident.Id == nil {
+		return incident, fmt.Errorf("incident id not sent")
+	}
+
+	result := db.Where("incident_id = ?", *event.Incident.Id).Limit(1).Find(&incident)
+	if result.Error != nil {
+		return incident, result.Error
+	}
+	if b.DryRun {
+		return incident, nil
+	}
+
+	incident.IncidentID = *event.Incident.Id
+	incident.LastAction = event.Action
+	if event.Incident.Status != nil {
+		incident.Status = string(*event.Incident.Status)
+	}
+	if event.Incident.Severity != nil {
+		incident.Severity = string(*event.Incident.Severity)
+	}
+	if event.Incident.GitguardianUrl != nil {
+		incident.GitguardianURL = *event.Incident.GitguardianUrl
+	}
+	if event.Incident.HmslHash != nil {
+		incident.HashPrefix = HashPrefix(*event.Incident.HmslHash)
+	}
+	switch b.IncidentBehavior(event.Action) {
+	case INCIDENT_ACTION_REMEDIATE:
+		incident.RemediationStatus = REMEDIATION_STATUS_OPEN
+	case INCIDENT_ACTION_CLOSE:
+		incident.RemediationStatus = REMEDIATION_STATUS_CLOSED
+	}
+
+	result = db.Save(&incident)
+	return incident, result.Error
+}
+
+// LinkIncidentAccounts links the accounts remediated for the incident
+func (b Brimstone) LinkIncidentAccounts(incident Incident, accounts []SafeHash) {
+	if b.DryRun || incident.ID == 0 || len(accounts) == 0 {
+		return
+	}
+	err := b.Db.Model(&incident).Association("Accounts").Append(accounts)
+	if err != nil {
+		log.Printf("ERROR: unable to link accounts to incident, %d: %s\n", incident.IncidentID, err.Error())
+	}
+}
+
+// MarkIncidentRemediated records when the remediation of the incident completed; an incident
+// with a failed rotation is left unmarked so reconciliation retries it, and an incident with a
+// pending rotation is marked once the CPM confirms every rotation, see checkRotation
+func (b Brimstone) MarkIncidentRemediated(incident Incident, events []RemediationEvent) {
+	if b.DryRun || incident.ID == 0 {
+		return
+	}
+	for i := 0; i < len(events); i++ {
+		if events[i].Error != "" || events[i].RotationStatus == ROTATION_PENDING {
+			return
+		}
+	}
+	result := b.Db.Model(&incident).Update("remediated_at", time.Now())
+	if result.Error != nil {
+		log.Printf("ERROR: unable to mark incident, %d, remediated: %s\n", incident.IncidentID, result.Error.Error())
+	}
+}
+
+// clearIncidentRemediated - the remediation of the incident needs a human, reconciliation
+// remediates it again
+func (b Brimstone) clearIncidentRemediated(incidentid int) {
+	result := b.Db.Model(&Incident{}).Where("incident_id = ?", incidentid).Update("remediated_at", nil)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to clear remediation of incident, %d: %s\n", incidentid, result.Error.Error())
+	}
+}
+
+// incidentAudit - audit event of the remediation of a GG incident, from the given source
+func incidentAudit(source string, event gg.IncidentEvent) RemediationEvent {
+	audit := RemediationEvent{
+		Source: source,
+	}
+	if event.Incident.Id != nil {
+		audit.IncidentID = *event.Incident.Id
+	}
+	if event.Incident.GitguardianUrl != nil {
+		audit.IncidentURL = *event.Incident.GitguardianUrl
+	}
+	if event.Incident.HmslHash != nil {
+		audit.HashPrefix = HashPrefix(*event.Incident.HmslHash)
+	}
+	return audit
+}
@@ -0,0 +1,133 @@
+package brimstone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIncidentActions(t *testing.T) {
+	actions, err := ParseIncidentActions(DEFAULT_INCIDENT_ACTIONS)
+	assert.NoError(t, err)
+	assert.Equal(t, INCIDENT_ACTION_REMEDIATE, actions["incident_reopened"])
+	assert.Equal(t, INCIDENT_ACTION_CLOSE, actions["incident_resolved"])
+
+	actions, err = ParseIncidentActions(" incident_triggered:remediate, incident_resolved:record ")
+	assert.NoError(t, err)
+	assert.Len(t, actions, 2)
+
+	_, err = ParseIncidentActions("incident_triggered")
+	assert.Error(t, err)
+	_, err = ParseIncidentActions("incident_triggered:rotate")
+	assert.Error(t, err)
+}
+
+func TestIncidentBehavior(t *testing.T) {
+	b := Brimstone{}
+	assert.Equal(t, INCIDENT_ACTION_REMEDIATE, b.IncidentBehavior("incident_triggered"))
+	assert.Equal(t, INCIDENT_ACTION_RECORD, b.IncidentBehavior("incident_severity_changed"))
+
+	b.IncidentActions = map[string]string{"incident_resolved": INCIDENT_ACTION_RECORD}
+	assert.Equal(t, INCIDENT_ACTION_RECORD, b.IncidentBehavior("incident_resolved"))
+	assert.Equal(t, INCIDENT_ACTION_RECORD, b.IncidentBehavior("incident_triggered"))
+}
+
+func TestSaveIncidentLifecycle(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	account := SafeHash{Safename: "safe1", Name: "1_1", Hash: "aaaa1111"}
+	b.Db.Create(&account)
+
+	id := 7
+	hash := "aaaa1111"
+	severity := gg.SeverityEnumHigh
+	event := gg.IncidentEvent{Action: "incident_triggered", Incident: gg.Incident{Id: &id, HmslHash: &hash, Severity: &severity}}
+	incident, err := b.SaveIncident(event)
+	assert.NoError(t, err)
+	assert.Equal(t, REMEDIATION_STATUS_OPEN, incident.RemediationStatus)
+	b.LinkIncidentAccounts(incident, []SafeHash{account})
+
+	event.Action = "incident_resolved"
+	incident, err = b.SaveIncident(event)
+	assert.NoError(t, err)
+	assert.Equal(t, REMEDIATION_STATUS_CLOSED, incident.RemediationStatus)
+
+	// actions without a configured behavior keep the remediation status
+	event.Action = "incident_assigned"
+	incident, err = b.SaveIncident(event)
+	assert.NoError(t, err)
+	assert.Equal(t, REMEDIATION_STATUS_CLOSED, incident.RemediationStatus)
+
+	var stored []Incident
+	b.Db.Preload("Accounts").Find(&stored)
+	assert.Len(t, stored, 1)
+	assert.Equal(t, "incident_assigned", stored[0].LastAction)
+	assert.Equal(t, "high", stored[0].Severity)
+	assert.Equal(t, "aaaa1", stored[0].HashPrefix)
+	assert.Len(t, stored[0].Accounts, 1)
+	assert.Equal(t, "1_1", stored[0].Accounts[0].Name)
+
+	// dry run does not change the stored incident
+	dryrun := b
+	dryrun.DryRun = true
+	event.Action = "incident_reopened"
+	incident, err = dryrun.SaveIncident(event)
+	assert.NoError(t, err)
+	assert.Equal(t, REMEDIATION_STATUS_CLOSED, incident.RemediationStatus)
+}
+
+func TestGitGuardianEventPostClose(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.PAMSessions = pam.NewSessionManager(pam.Config{})
+
+	e := echo.New()
+	body := `{"action":"incident_resolved","incident":{"id":9,"status":"RESOLVED"}}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/notify/ggevent", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, b.GitGuardianEventPost(e.NewContext(req, rec), GitGuardianEventPostParams{}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var incident Incident
+	assert.NoError(t, b.Db.Where("incident_id = ?", 9).First(&incident).Error)
+	assert.Equal(t, REMEDIATION_STATUS_CLOSED, incident.RemediationStatus)
+	assert.Equal(t, "RESOLVED", incident.Status)
+}
+
+func TestDeleteIncidentLinkedHashes(t *testing.T) {
+	// foreign keys are on, as with postgres, see newTestBrimstone
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	createHashVersions(t, b, "safe1", "1_1", []int{0, 10, 20, 30})
+	createHashVersions(t, b, "safe1", "1_2", []int{0})
+	createHashVersions(t, b, "safe2", "2_1", []int{0})
+	createHashVersions(t, b, "safe3", "3_1", []int{0, 10, 20, 30})
+
+	var linked []SafeHash
+	b.Db.Where("name IN ?", []string{"1_2", "2_1"}).Or("name = ? AND hash = ?", "1_1", "1_1-30").Find(&linked)
+	assert.Len(t, linked, 3)
+	incident := Incident{IncidentID: 7}
+	b.Db.Create(&incident)
+	b.LinkIncidentAccounts(incident, linked)
+
+	// the linked oldest version of 1_1 is pruned, and the later safes are still pruned
+	count, err := b.PruneSafeHashes()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	assert.NoError(t, b.SafeAccountDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe1", "1_2"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, b.SafeDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe2"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var stored Incident
+	assert.NoError(t, b.Db.Preload("Accounts").First(&stored, incident.ID).Error)
+	assert.Empty(t, stored.Accounts)
+}
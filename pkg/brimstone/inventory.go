@@ -0,0 +1,155 @@
+package brimstone
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// context key set by the api key middleware when the request uses the admin api key
+const ADMIN_CONTEXT_KEY = "brimstone_admin"
+
+// default and max number of hashes per page of the hash inventory
+const (
+	DEFAULT_HASH_PAGE_SIZE = 100
+	MAX_HASH_PAGE_SIZE     = 1000
+)
+
+// IsAdmin - true when the request was authenticated with the admin api key
+func IsAdmin(ctx echo.Context) bool {
+	admin, ok := ctx.Get(ADMIN_CONTEXT_KEY).(bool)
+	return ok && admin
+}
+
+// HashesGet - GET /v1/hashes
+func (b Brimstone) HashesGet(ctx echo.Context, params HashesGetParams) error {
+	db := b.Db
+
+	full := params.Full != nil && *params.Full
+	if full && !IsAdmin(ctx) {
+		return sendBrimstoneError(ctx, http.StatusForbidden, "Full hashes require the admin api key")
+	}
+
+	page := 1
+	if params.Page != nil && *params.Page > 0 {
+		page = *params.Page
+	}
+	pagesize := DEFAULT_HASH_PAGE_SIZE
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pagesize = utils.MinInt(*params.PageSize, MAX_HASH_PAGE_SIZE)
+	}
+
+	query := db.Model(&SafeHash{})
+	if params.Safename != nil {
+		query = query.Where("safename = ?", *params.Safename)
+	}
+	if params.Accountid != nil {
+		query = query.Where("name = ?", *params.Accountid)
+	}
+
+	var total int64
+	result := query.Count(&total)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to count hashes")
+	}
+
+	var hashes []SafeHash
+	result = query.Order("safename, name, created_at desc").Offset((page - 1) * pagesize).Limit(pagesize).Find(&hashes)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch hashes")
+	}
+
+	rsp := StoredHashPage{
+		Page:     page,
+		PageSize: pagesize,
+		Total:    int(total),
+		Hashes:   []StoredHash{},
+	}
+	for i := 0; i < len(hashes); i++ {
+		h := StoredHash{
+			Safename:   hashes[i].Safename,
+			Accountid:  hashes[i].Name,
+			HashPrefix: HashPrefix(hashes[i].Hash),
+			CreatedAt:  hashes[i].CreatedAt,
+		}
+		if full {
+			h.Hash = &hashes[i].Hash
+		}
+		rsp.Hashes = append(rsp.Hashes, h)
+	}
+	return ctx.JSON(200, rsp)
+}
+
+// SafesGet - GET /v1/safes
+func (b Brimstone) SafesGet(ctx echo.Context) error {
+	db := b.Db
+
+	var summaries []SafeSummary
+	result := db.Model(&SafeHash{}).
+		Select("safename, count(distinct name) as accountcount, count(*) as hashcount").
+		Group("safename").Order("safename").
+		Scan(&summaries)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch safes")
+	}
+
+	// newest hash of each safe tells when it was last updated
+	for i := 0; i < len(summaries); i++ {
+		var newest []SafeHash
+		db.Where(&SafeHash{Safename: summaries[i].Safename}).Order("created_at desc").Limit(1).Find(&newest)
+		if len(newest) > 0 {
+			summaries[i].LastUpdated = &newest[0].CreatedAt
+		}
+	}
+
+	if summaries == nil {
+		summaries = []SafeSummary{}
+	}
+	return ctx.JSON(200, summaries)
+}
+
+// DeleteSafeHashes removes the hash versions matching the condition, and their incident links
+// first, the join table references the versions; returns the number of versions removed
+func (b Brimstone) DeleteSafeHashes(query interface{}, args ...interface{}) (int64, error) {
+	var deleted int64
+	err := b.Db.Transaction(func(tx *gorm.DB) error {
+		hashids := tx.Unscoped().Model(&SafeHash{}).Select("id").Where(query, args...)
+		result := tx.Exec("DELETE FROM incident_accounts WHERE safe_hash_id IN (?)", hashids)
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Unscoped().Where(query, args...).Delete(&SafeHash{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// SafeDelete - DELETE /v1/safes/{safename}
+func (b Brimstone) SafeDelete(ctx echo.Context, safename string) error {
+	deleted, err := b.DeleteSafeHashes("safename = ?", safename)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to delete safe hashes")
+	}
+	if deleted == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("safe, %s, not found", safename))
+	}
+
+	return ctx.JSON(200, "deleted")
+}
+
+// SafeAccountDelete - DELETE /v1/safes/{safename}/accounts/{accountid}
+func (b Brimstone) SafeAccountDelete(ctx echo.Context, safename string, accountid string) error {
+	deleted, err := b.DeleteSafeHashes("safename = ? AND name = ?", safename, accountid)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to delete account hashes")
+	}
+	if deleted == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("account id, %s, not found in safe, %s", accountid, safename))
+	}
+
+	return ctx.JSON(200, "deleted")
+}
@@ -0,0 +1,100 @@
+package brimstone
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashesGet(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: "aaaa1111"},
+		{Safename: "safe1", Name: "1_2", Hash: "bbbb2222"},
+		{Safename: "safe1", Name: "1_3", Hash: "cccc3333"},
+		{Safename: "safe2", Name: "2_1", Hash: "dddd4444"},
+	})
+
+	e := echo.New()
+	safename := "safe1"
+	page := 2
+	pagesize := 2
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/hashes", nil), rec)
+	err := b.HashesGet(ctx, HashesGetParams{Safename: &safename, Page: &page, PageSize: &pagesize})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rsp StoredHashPage
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	assert.Equal(t, 3, rsp.Total)
+	assert.Len(t, rsp.Hashes, 1)
+	assert.Equal(t, "1_3", rsp.Hashes[0].Accountid)
+	assert.Equal(t, "cccc3", rsp.Hashes[0].HashPrefix)
+	assert.Nil(t, rsp.Hashes[0].Hash)
+
+	// full hashes require the admin api key
+	full := true
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/hashes?full=true", nil), rec)
+	assert.NoError(t, b.HashesGet(ctx, HashesGetParams{Full: &full}))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	ctx = e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/hashes?full=true", nil), rec)
+	ctx.Set(ADMIN_CONTEXT_KEY, true)
+	assert.NoError(t, b.HashesGet(ctx, HashesGetParams{Full: &full}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	assert.Equal(t, 4, rsp.Total)
+	assert.Equal(t, "aaaa1111", *rsp.Hashes[0].Hash)
+}
+
+func TestSafesGetAndDelete(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: "aaaa1111"},
+		{Safename: "safe1", Name: "1_1", Hash: "aaaa2222"},
+		{Safename: "safe1", Name: "1_2", Hash: "bbbb2222"},
+		{Safename: "safe2", Name: "2_1", Hash: "dddd4444"},
+	})
+	b.Db.Create(&SafePolicy{Safename: "safe2", MaxHashCount: 5})
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	assert.NoError(t, b.SafesGet(e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/safes", nil), rec)))
+	var safes []SafeSummary
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &safes))
+	assert.Len(t, safes, 2)
+	assert.Equal(t, "safe1", safes[0].Safename)
+	assert.Equal(t, 2, safes[0].Accountcount)
+	assert.Equal(t, 3, safes[0].Hashcount)
+	assert.NotNil(t, safes[0].LastUpdated)
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, b.SafeAccountDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe1", "1_1"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, b.SafeDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe2"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, b.SafeDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe2"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var remaining []SafeHash
+	b.Db.Find(&remaining)
+	assert.Len(t, remaining, 1)
+	assert.Equal(t, "1_2", remaining[0].Name)
+
+	// the purged safe keeps its retention policy
+	policy, saved, err := b.FetchSafePolicy("safe2")
+	assert.NoError(t, err)
+	assert.True(t, saved)
+	assert.Equal(t, 5, policy.MaxHashCount)
+}
@@ -0,0 +1,210 @@
+package brimstone
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Hash retention of a safe; versions are kept while they are one of the newest MaxHashCount
+// versions of an account or younger than MaxAgeDays
+type SafePolicy struct {
+	gorm.Model
+	Safename     string `gorm:"uniqueIndex"`
+	MaxHashCount int
+	MaxAgeDays   int
+}
+
+// DefaultSafePolicy - retention of a safe without a saved policy
+func DefaultSafePolicy(safename string) SafePolicy {
+	return SafePolicy{
+		Safename:     safename,
+		MaxHashCount: MAX_HASH_COUNT,
+		MaxAgeDays:   0,
+	}
+}
+
+// Validate checks the policy keeps at least the current hash
+func (p SafePolicy) Validate() error {
+	if p.MaxHashCount < 1 {
+		return fmt.Errorf("maxhashcount must be at least 1")
+	}
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("maxagedays must not be negative")
+	}
+	return nil
+}
+
+// FetchSafePolicy - given safename return its retention policy, or the default policy
+func (b Brimstone) FetchSafePolicy(safename string) (SafePolicy, bool, error) {
+	db := b.Db
+
+	var policies []SafePolicy
+	result := db.Where(&SafePolicy{Safename: safename}).Limit(1).Find(&policies)
+	if result.Error != nil {
+		return SafePolicy{}, false, result.Error
+	}
+	if len(policies) == 0 {
+		return DefaultSafePolicy(safename), false, nil
+	}
+	return policies[0], true, nil
+}
+
+// PruneSafeHashes removes the hash versions of every safe that fall outside its retention
+// policy, a safe that fails does not stop the others; returns the number of versions removed
+func (b Brimstone) PruneSafeHashes() (int64, error) {
+	safenames, err := b.FetchSafenames()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	var errs []error
+	for i := 0; i < len(safenames); i++ {
+		policy, _, err := b.FetchSafePolicy(safenames[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("safe, %s: %w", safenames[i], err))
+			continue
+		}
+		count, err := b.PruneSafe(policy, time.Now())
+		total += count
+		if err != nil {
+			errs = append(errs, fmt.Errorf("safe, %s: %w", safenames[i], err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// PruneSafe removes the hash versions of the safe that fall outside the policy; the newest
+// version of an account is always kept
+func (b Brimstone) PruneSafe(policy SafePolicy, now time.Time) (int64, error) {
+	db := b.Db
+
+	var hashes []SafeHash
+	result := db.Where(&SafeHash{Safename: policy.Safename}).Order("name, created_at desc, id desc").Find(&hashes)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	cutoff := now.AddDate(0, 0, -policy.MaxAgeDays)
+	var expired []uint
+	name := ""
+	version := 0
+	for i := 0; i < len(hashes); i++ {
+		if i == 0 || hashes[i].Name != name {
+			name = hashes[i].Name
+			version = 0
+		}
+		version++
+		if version == 1 || version <= policy.MaxHashCount {
+			continue
+		}
+		if policy.MaxAgeDays > 0 && hashes[i].CreatedAt.After(cutoff) {
+			continue
+		}
+		expired = append(expired, hashes[i].ID)
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	return b.DeleteSafeHashes("id IN ?", expired)
+}
+
+// RunPruneSafeHashes - maintenance task run by the scheduler
+func (b Brimstone) RunPruneSafeHashes() {
+	count, err := b.PruneSafeHashes()
+	if err != nil {
+		log.Printf("ERROR: pruning safe hashes: %s\n", err.Error())
+		return
+	}
+	log.Printf("INFO: pruned %d hash version(s) outside of the safe retention policies\n", count)
+}
+
+func safePolicyToAPI(p SafePolicy, saved bool) RetentionPolicy {
+	isdefault := !saved
+	return RetentionPolicy{
+		Safename:     &p.Safename,
+		Maxhashcount: &p.MaxHashCount,
+		Maxagedays:   &p.MaxAgeDays,
+		Default:      &isdefault,
+	}
+}
+
+// SafePoliciesGet - GET /v1/policies
+func (b Brimstone) SafePoliciesGet(ctx echo.Context) error {
+	db := b.Db
+
+	var policies []SafePolicy
+	result := db.Order("safename").Find(&policies)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch retention policies")
+	}
+
+	rsp := []RetentionPolicy{}
+	for i := 0; i < len(policies); i++ {
+		rsp = append(rsp, safePolicyToAPI(policies[i], true))
+	}
+	return ctx.JSON(200, rsp)
+}
+
+// SafePolicyGet - GET /v1/safes/{safename}/policy
+func (b Brimstone) SafePolicyGet(ctx echo.Context, safename string) error {
+	policy, saved, err := b.FetchSafePolicy(safename)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch retention policy")
+	}
+	return ctx.JSON(200, safePolicyToAPI(policy, saved))
+}
+
+// SafePolicyPut - PUT /v1/safes/{safename}/policy
+func (b Brimstone) SafePolicyPut(ctx echo.Context, safename string) error {
+	db := b.Db
+
+	var req RetentionPolicy
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for RetentionPolicy")
+	}
+
+	policy, _, err := b.FetchSafePolicy(safename)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch retention policy")
+	}
+	if req.Maxhashcount != nil {
+		policy.MaxHashCount = *req.Maxhashcount
+	}
+	if req.Maxagedays != nil {
+		policy.MaxAgeDays = *req.Maxagedays
+	}
+	if err := policy.Validate(); err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result := db.Save(&policy)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to save retention policy")
+	}
+
+	return ctx.JSON(200, safePolicyToAPI(policy, true))
+}
+
+// SafePolicyDelete - DELETE /v1/safes/{safename}/policy
+func (b Brimstone) SafePolicyDelete(ctx echo.Context, safename string) error {
+	db := b.Db
+
+	result := db.Unscoped().Where("safename = ?", safename).Delete(&SafePolicy{})
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to delete retention policy")
+	}
+	if result.RowsAffected == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("no retention policy for safe, %s", safename))
+	}
+
+	return ctx.JSON(200, "deleted")
+}
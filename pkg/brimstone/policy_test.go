@@ -0,0 +1,63 @@
+package brimstone
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createHashVersions(t *testing.T, b Brimstone, safename string, name string, ages []int) {
+	now := time.Now()
+	for i := 0; i < len(ages); i++ {
+		h := SafeHash{
+			Safename: safename,
+			Name:     name,
+			Hash:     name + "-" + strconv.Itoa(ages[i]),
+		}
+		h.CreatedAt = now.AddDate(0, 0, -ages[i])
+		assert.NoError(t, b.Db.Create(&h).Error)
+	}
+}
+
+func TestPruneSafeDefaultPolicy(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	createHashVersions(t, b, "safe1", "1_1", []int{0, 10, 20, 30, 40})
+	createHashVersions(t, b, "safe1", "1_2", []int{5})
+
+	count, err := b.PruneSafeHashes()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	var remaining []SafeHash
+	b.Db.Where(&SafeHash{Safename: "safe1", Name: "1_1"}).Order("created_at desc").Find(&remaining)
+	assert.Len(t, remaining, MAX_HASH_COUNT)
+	assert.Equal(t, "1_1-0", remaining[0].Hash)
+}
+
+func TestPruneSafeAgePolicy(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	createHashVersions(t, b, "safe1", "1_1", []int{0, 10, 20, 200, 400})
+	policy := SafePolicy{Safename: "safe1", MaxHashCount: 1, MaxAgeDays: 180}
+	assert.NoError(t, b.Db.Create(&policy).Error)
+
+	count, err := b.PruneSafeHashes()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestPruneSafeKeepsCurrentHash(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	createHashVersions(t, b, "safe2", "2_1", []int{400})
+
+	count, err := b.PruneSafe(SafePolicy{Safename: "safe2", MaxHashCount: 1, MaxAgeDays: 1}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestSafePolicyValidate(t *testing.T) {
+	assert.NoError(t, DefaultSafePolicy("safe1").Validate())
+	assert.Error(t, SafePolicy{MaxHashCount: 0}.Validate())
+	assert.Error(t, SafePolicy{MaxHashCount: 1, MaxAgeDays: -1}.Validate())
+}
@@ -0,0 +1,250 @@
+package brimstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// name of the checkpoint of the GG incident reconciliation
+const RECONCILE_CHECKPOINT_GG_INCIDENTS = "gg_incidents"
+
+// number of GG incidents fetched per page while reconciling
+const RECONCILE_PAGE_SIZE = 100
+
+// Outcome of the reconciliation of one incident
+const (
+	RECONCILE_SKIPPED    = "skipped"
+	RECONCILE_REMEDIATED = "remediated"
+	RECONCILE_FAILED     = "failed"
+)
+
+// ReconcileCheckpoint - date of the last GG incident reconciled without failures; the next
+// reconciliation resumes from it
+type ReconcileCheckpoint struct {
+	gorm.Model
+	Name           string `gorm:"uniqueIndex"`
+	LastDate       time.Time
+	LastIncidentID int
+}
+
+// Errors returned before the reconciliation starts
+var (
+	ErrReconcileRunning   = errors.New("a reconciliation is already running")
+	ErrReconcileStartDate = errors.New("start date is required until a first reconciliation saved a checkpoint")
+)
+
+// lockReconcile - claim the reconcile lock so reconciliations never overlap
+func (b Brimstone) lockReconcile() (func(), bool) {
+	if b.Scheduler == nil {
+		return func() {}, true
+	}
+	if !b.Scheduler.reconciling.TryLock() {
+		return nil, false
+	}
+	return b.Scheduler.reconciling.Unlock, true
+}
+
+// FetchReconcileCheckpoint - the saved checkpoint, or an empty checkpoint before the first reconciliation
+func (b Brimstone) FetchReconcileCheckpoint() (ReconcileCheckpoint, error) {
+	var checkpoints []ReconcileCheckpoint
+	result := b.Db.Where("name = ?", RECONCILE_CHECKPOINT_GG_INCIDENTS).Limit(1).Find(&checkpoints)
+	if result.Error != nil {
+		return ReconcileCheckpoint{}, result.Error
+	}
+	if len(checkpoints) == 0 {
+		return ReconcileCheckpoint{Name: RECONCILE_CHECKPOINT_GG_INCIDENTS}, nil
+	}
+	return checkpoints[0], nil
+}
+
+// ReconcileIncidents pages through the GG incidents with the given status created since the start
+// date, or the checkpoint when since is nil, and remediates the ones brimstone has not remediated yet,
+// the same way as a webhook incident_triggered event. The checkpoint advances up to the first failure
+// so failed incidents are retried by the next reconciliation.
+func (b Brimstone) ReconcileIncidents(ctx context.Context, since *time.Time, status gg.StatusEnum) (ReconcileResult, error) {
+	var result ReconcileResult
+	client := b.GGClient
+	if client == nil {
+		return result, fmt.Errorf("GG api client is not configured")
+	}
+	unlock, ok := b.lockReconcile()
+	if !ok {
+		return result, ErrReconcileRunning
+	}
+	defer unlock()
+
+	checkpoint, err := b.FetchReconcileCheckpoint()
+	if err != nil {
+		return result, err
+	}
+	result.Since = checkpoint.LastDate
+	if since != nil {
+		result.Since = *since
+	}
+	if result.Since.IsZero() {
+		return result, ErrReconcileStartDate
+	}
+
+	dateafter := result.Since.UTC().Format(time.RFC3339)
+	ordering := gg.ListIncidentsParamsOrderingDate
+	pagesize := RECONCILE_PAGE_SIZE
+	params := gg.ListIncidentsParams{
+		DateAfter: &dateafter,
+		Ordering:  &ordering,
+		PerPage:   &pagesize,
+		Status:    &status,
+	}
+
+	advance := true
+	for {
+		res, err := client.ListIncidentsWithResponse(ctx, &params)
+		if err == nil && res.JSON200 == nil {
+			err = fmt.Errorf("unable to list GG incidents: %s", res.Status())
+		}
+		if err != nil {
+			b.saveReconcileCheckpoint(checkpoint)
+			return result, err
+		}
+
+		incidents := *res.JSON200
+		for i := 0; i < len(incidents); i++ {
+			result.Listed++
+			switch b.reconcileIncident(ctx, incidents[i]) {
+			case RECONCILE_SKIPPED:
+				result.Skipped++
+			case RECONCILE_REMEDIATED:
+				result.Remediated++
+			case RECONCILE_FAILED:
+				result.Failed++
+				advance = false
+			}
+			if advance && incidents[i].Date != nil && incidents[i].Id != nil {
+				checkpoint.LastDate = *incidents[i].Date
+				checkpoint.LastIncidentID = *incidents[i].Id
+			}
+		}
+
+		cursor := gg.NextCursor(res.HTTPResponse)
+		if cursor == "" {
+			break
+		}
+		params.Cursor = &cursor
+	}
+
+	b.saveReconcileCheckpoint(checkpoint)
+	if !checkpoint.LastDate.IsZero() {
+		result.Checkpoint = &checkpoint.LastDate
+	}
+	return result, nil
+}
+
+func (b Brimstone) saveReconcileCheckpoint(checkpoint ReconcileCheckpoint) {
+	if b.DryRun || checkpoint.LastDate.IsZero() {
+		return
+	}
+	result := b.Db.Save(&checkpoint)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to save reconcile checkpoint: %s\n", result.Error.Error())
+	}
+}
+
+// reconcileIncident remediates a GG incident listed by the reconciliation; returns the outcome
+func (b Brimstone) reconcileIncident(ctx context.Context, listed gg.IncidentWithoutOccurrences) string {
+	if listed.Id == nil || listed.HmslHash == nil {
+		return RECONCILE_SKIPPED
+	}
+
+	var existing []Incident
+	result := b.Db.Where("incident_id = ?", *listed.Id).Limit(1).Find(&existing)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to fetch incident, %d: %s\n", *listed.Id, result.Error.Error())
+		return RECONCILE_FAILED
+	}
+	if len(existing) > 0 && (existing[0].RemediatedAt != nil || existing[0].RemediationStatus == REMEDIATION_STATUS_CLOSED) {
+		return RECONCILE_SKIPPED
+	}
+
+	// handled like the webhook event that was missed
+	event := gg.IncidentEvent{
+		Action: "incident_triggered",
+		Incident: gg.Incident{
+			Id:             listed.Id,
+			GitguardianUrl: listed.GitguardianUrl,
+			HmslHash:       listed.HmslHash,
+			SecretHash:     listed.SecretHash,
+			Severity:       listed.Severity,
+			Status:         listed.Status,
+			Date:           listed.Date,
+		},
+	}
+	if b.IncidentBehavior(event.Action) != INCIDENT_ACTION_REMEDIATE {
+		return RECONCILE_SKIPPED
+	}
+
+	incident, err := b.SaveIncident(event)
+	if err != nil {
+		log.Printf("ERROR: unable to save incident, %d: %s\n", *listed.Id, err.Error())
+		return RECONCILE_FAILED
+	}
+	accounts, _, err := b.RemediateIncident(ctx, event, incident, incidentAudit(AUDIT_SOURCE_GG_RECONCILE, event))
+	if err != nil {
+		log.Printf("ERROR: unable to remediate incident, %d: %s\n", *listed.Id, err.Error())
+		return RECONCILE_FAILED
+	}
+	for i := 0; i < len(accounts); i++ {
+		if !accounts[i].DryRun && !accounts[i].Rotated && !accounts[i].Added && accounts[i].RotationStatus != ROTATION_PENDING {
+			return RECONCILE_FAILED
+		}
+	}
+	log.Printf("INFO: reconciled incident, %d\n", *listed.Id)
+	return RECONCILE_REMEDIATED
+}
+
+// RunReconcileIncidents - maintenance task run by the scheduler, resumes from the checkpoint
+func (b Brimstone) RunReconcileIncidents() {
+	checkpoint, err := b.FetchReconcileCheckpoint()
+	if err != nil {
+		log.Printf("ERROR: reconciling GG incidents: %s\n", err.Error())
+		return
+	}
+	if checkpoint.LastDate.IsZero() {
+		log.Printf("INFO: no reconcile checkpoint yet, POST /v1/incidents/reconcile?since=<start date> to start reconciling GG incidents\n")
+		return
+	}
+	result, err := b.ReconcileIncidents(context.Background(), nil, gg.StatusEnumTRIGGERED)
+	if err != nil {
+		log.Printf("ERROR: reconciling GG incidents: %s\n", err.Error())
+		return
+	}
+	log.Printf("INFO: reconciled GG incidents, listed: %d, skipped: %d, remediated: %d, failed: %d\n", result.Listed, result.Skipped, result.Remediated, result.Failed)
+}
+
+// IncidentsReconcilePost - POST /v1/incidents/reconcile
+func (b Brimstone) IncidentsReconcilePost(ctx echo.Context, params IncidentsReconcilePostParams) error {
+	b = b.withDryRun(params.DryRun)
+
+	status := gg.StatusEnumTRIGGERED
+	if params.Status != nil {
+		status = gg.StatusEnum(*params.Status)
+	}
+	result, err := b.ReconcileIncidents(ctx.Request().Context(), params.Since, status)
+	if errors.Is(err, ErrReconcileRunning) {
+		return sendBrimstoneError(ctx, http.StatusConflict, err.Error())
+	}
+	if errors.Is(err, ErrReconcileStartDate) {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		log.Printf("ERROR: reconciling GG incidents: %s\n", err.Error())
+		return sendBrimstoneError(ctx, http.StatusBadGateway, err.Error())
+	}
+	return ctx.JSON(200, result)
+}
@@ -0,0 +1,111 @@
+package brimstone
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPAMStub - PAM stub that issues tokens and changes passwords, except for the failing account ids
+func newPAMStub(t *testing.T, failing ...string) *pam.SessionManager {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/platformtoken" {
+			w.Write([]byte(`{"token_type":"Bearer","access_token":"pamtoken"}`))
+			return
+		}
+		for i := 0; i < len(failing); i++ {
+			if strings.Contains(r.URL.Path, "/Accounts/"+failing[i]+"/") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return pam.NewSessionManager(pam.Config{IDTenantURL: server.URL, PCloudURL: server.URL})
+}
+
+func TestReconcileIncidents(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.PAMSessions = newPAMStub(t, "2_1")
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: "hash1"},
+		{Safename: "safe2", Name: "2_1", Hash: "hash2"},
+	})
+	remediatedat := time.Now()
+	b.Db.Create(&Incident{IncidentID: 3, RemediationStatus: REMEDIATION_STATUS_OPEN, RemediatedAt: &remediatedat})
+
+	var listed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/incidents/secrets" {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("{}"))
+			return
+		}
+		listed = append(listed, r.URL.RawQuery)
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/v1/incidents/secrets?cursor=page2>; rel="next"`, r.Host))
+			w.Write([]byte(`[
+				{"id": 1, "date": "2024-01-01T00:00:00Z"},
+				{"id": 2, "date": "2024-01-02T00:00:00Z", "hmsl_hash": "hash1"},
+				{"id": 3, "date": "2024-01-03T00:00:00Z", "hmsl_hash": "hash1"}
+			]`))
+			return
+		}
+		w.Write([]byte(`[{"id": 4, "date": "2024-01-04T00:00:00Z", "hmsl_hash": "hash2"}]`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := gg.NewClientWithToken(server.URL, "ggtoken")
+	assert.NoError(t, err)
+	b.GGClient = client
+
+	// the first reconciliation needs a start date
+	_, err = b.ReconcileIncidents(context.Background(), nil, gg.StatusEnumTRIGGERED)
+	assert.ErrorIs(t, err, ErrReconcileStartDate)
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := b.ReconcileIncidents(context.Background(), &since, gg.StatusEnumTRIGGERED)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Listed)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 1, result.Remediated)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, listed, 2)
+	assert.Contains(t, listed[0], "status=TRIGGERED")
+	assert.Contains(t, listed[0], "date_after=2024-01-01T00%3A00%3A00Z")
+
+	// the failed incident holds the checkpoint back
+	checkpoint, err := b.FetchReconcileCheckpoint()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, checkpoint.LastIncidentID)
+	assert.True(t, checkpoint.LastDate.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)))
+
+	var incident Incident
+	assert.NoError(t, b.Db.Where("incident_id = ?", 2).First(&incident).Error)
+	assert.NotNil(t, incident.RemediatedAt)
+	var failed Incident
+	assert.NoError(t, b.Db.Where("incident_id = ?", 4).First(&failed).Error)
+	assert.Nil(t, failed.RemediatedAt)
+
+	var events []RemediationEvent
+	b.Db.Where("source = ?", AUDIT_SOURCE_GG_RECONCILE).Order("incident_id").Find(&events)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "1_1", events[0].AccountID)
+
+	// the next reconciliation resumes from the checkpoint and retries the failed incident
+	listed = nil
+	result, err = b.ReconcileIncidents(context.Background(), nil, gg.StatusEnumTRIGGERED)
+	assert.NoError(t, err)
+	assert.Contains(t, listed[0], "date_after=2024-01-03T00%3A00%3A00Z")
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 3, result.Skipped)
+}
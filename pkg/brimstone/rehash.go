@@ -0,0 +1,75 @@
+package brimstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+)
+
+// ErrHashUnchanged - the password retrieved after a confirmed rotation still has the leaked hash
+var ErrHashUnchanged = errors.New("password unchanged after rotation, the new hash matches the leaked hash")
+
+// leakedHash - the hash brimstone holds for the account when its rotation is requested, or the
+// leaked hash prefix of the remediation when brimstone does not track the account; taken before
+// the change, as hailstone or the CPM plugin may save the hash of the new password first
+func (b Brimstone) leakedHash(audit RemediationEvent) string {
+	var hashes []SafeHash
+	b.Db.Where("safename = ? AND name = ?", audit.Safename, audit.AccountID).Order("created_at desc, id desc").Limit(1).Find(&hashes)
+	if len(hashes) > 0 {
+		return hashes[0].Hash
+	}
+	return audit.HashPrefix
+}
+
+// RehashAccount retrieves the password of a rotated account, saves its hmsl hash as the next
+// version, the same as the CPM plugin event, and verifies it differs from leaked, the hash or
+// hash prefix saved with the rotation request; used when the CPM plugin is not installed on the
+// account platform. audit is the rotate event, its source and incident are recorded with the
+// update_hash event, and its hash prefix is compared when leaked is empty.
+func (b Brimstone) RehashAccount(ctx context.Context, client *pam.Client, safename string, accountid string, leaked string, audit RemediationEvent) error {
+	rehashaudit := RemediationEvent{
+		Source:     audit.Source,
+		IncidentID: audit.IncidentID,
+		Safename:   safename,
+		AccountID:  accountid,
+		Action:     AUDIT_ACTION_UPDATE_HASH,
+	}
+
+	password, err := client.FetchAccountPassword(ctx, accountid)
+	if err != nil {
+		log.Printf("ERROR: unable to retrieve password of rotated account, %s: %s\n", accountid, err.Error())
+		rehashaudit.ResponseCode = pam.StatusCode(err)
+		rehashaudit.Error = fmt.Sprintf("unable to retrieve password: %s", err.Error())
+		b.RecordRemediation(rehashaudit)
+		return err
+	}
+	newhash, err := hmsl.ComputeHash(password)
+	if err != nil {
+		log.Printf("ERROR: unable to compute hash of rotated account, %s: %s\n", accountid, err.Error())
+		rehashaudit.Error = fmt.Sprintf("unable to compute hash: %s", err.Error())
+		b.RecordRemediation(rehashaudit)
+		return err
+	}
+	rehashaudit.HashPrefix = HashPrefix(newhash)
+
+	if leaked == "" {
+		leaked = audit.HashPrefix
+	}
+	if leaked != "" && (newhash == leaked || (len(leaked) < len(newhash) && HashPrefix(newhash) == leaked)) {
+		rehashaudit.Action = AUDIT_ACTION_SKIP
+		rehashaudit.Error = ErrHashUnchanged.Error()
+		b.RecordRemediation(rehashaudit)
+		return ErrHashUnchanged
+	}
+
+	log.Printf("saving next version of hash after rotation, safename: %s, account id: %s\n", safename, accountid)
+	batch := HashBatch{Safename: safename, Hashes: []Hash{{Name: accountid, Hash: newhash}}}
+	err = b.SaveSafeHashVersions(batch)
+	rehashaudit.Error = errorString(err)
+	b.RecordRemediation(rehashaudit)
+	return err
+}
@@ -0,0 +1,118 @@
+package brimstone
+
+import (
+	"context"
+	"testing"
+
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/stretchr/testify/assert"
+)
+
+// rotateLeakedAccount - tracks the hash of the account, then requests its rotation
+func rotateLeakedAccount(t *testing.T, b Brimstone, leakedpassword string) RemediationEvent {
+	leaked, err := hmsl.ComputeHash(leakedpassword)
+	assert.NoError(t, err)
+	b.Db.Create(&SafeHash{Safename: "safe1", Name: "1_1", Hash: leaked})
+
+	client := b.PAMSessions.Client()
+	audit, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Safename: "safe1", AccountID: "1_1", HashPrefix: HashPrefix(leaked)})
+	assert.NoError(t, err)
+	return audit
+}
+
+func TestRehashAfterRotation(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	b.RehashAfterRotation = true
+	rotateLeakedAccount(t, b, "leaked")
+
+	stub.password = "rotated"
+	stub.set(CPM_STATUS_SUCCESS, 2000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Succeeded: 1}, result)
+
+	// the new hash is the current version, the leaked one is kept per the retention policy
+	rotated, _ := hmsl.ComputeHash("rotated")
+	var hashes []SafeHash
+	b.Db.Where("safename = ? AND name = ?", "safe1", "1_1").Order("id").Find(&hashes)
+	assert.Len(t, hashes, 2)
+	assert.Equal(t, rotated, hashes[1].Hash)
+
+	var events []RemediationEvent
+	b.Db.Where("action = ?", AUDIT_ACTION_UPDATE_HASH).Find(&events)
+	assert.Len(t, events, 1)
+	assert.Equal(t, AUDIT_SOURCE_HMSL_SCAN, events[0].Source)
+	assert.Equal(t, HashPrefix(rotated), events[0].HashPrefix)
+	assert.Equal(t, "", events[0].Error)
+}
+
+func TestRehashAfterRotationUnchanged(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	b.RehashAfterRotation = true
+	audit := rotateLeakedAccount(t, b, "leaked")
+
+	// the CPM reports a change, but the password is still the leaked one
+	stub.password = "leaked"
+	stub.set(CPM_STATUS_SUCCESS, 2000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Escalated: 1}, result)
+
+	var rotation AccountRotation
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Equal(t, ROTATION_ESCALATED, rotation.Status)
+	assert.Equal(t, ErrHashUnchanged.Error(), rotation.Error)
+	assert.NoError(t, b.Db.First(&audit, audit.ID).Error)
+	assert.Equal(t, ROTATION_ESCALATED, audit.RotationStatus)
+
+	var count int64
+	b.Db.Model(&SafeHash{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRehashAfterRotationHashAlreadySaved(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	b.RehashAfterRotation = true
+	rotateLeakedAccount(t, b, "leaked")
+
+	var rotation AccountRotation
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	leaked, _ := hmsl.ComputeHash("leaked")
+	assert.Equal(t, leaked, rotation.LeakedHash)
+
+	// hailstone saves the hash of the new password before the rotation is checked
+	rotated, _ := hmsl.ComputeHash("rotated")
+	b.Db.Create(&SafeHash{Safename: "safe1", Name: "1_1", Hash: rotated})
+
+	stub.password = "rotated"
+	stub.set(CPM_STATUS_SUCCESS, 2000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Succeeded: 1}, result)
+
+	var count int64
+	b.Db.Model(&SafeHash{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+	var event RemediationEvent
+	assert.NoError(t, b.Db.Where("action = ?", AUDIT_ACTION_UPDATE_HASH).First(&event).Error)
+	assert.Equal(t, "", event.Error)
+}
+
+func TestRehashWithoutTrackedHash(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	client := b.PAMSessions.Client()
+	stub.password = "rotated"
+
+	// brimstone only knows the leaked prefix, the account hash is saved as a new name of the safe
+	err := b.RehashAccount(context.Background(), &client, "safe1", "1_1", "", RemediationEvent{Source: AUDIT_SOURCE_GG_WEBHOOK, IncidentID: 7, HashPrefix: "00000"})
+	assert.NoError(t, err)
+
+	var hashes []SafeHash
+	b.Db.Find(&hashes)
+	assert.Len(t, hashes, 1)
+	assert.Equal(t, "1_1", hashes[0].Name)
+
+	var event RemediationEvent
+	assert.NoError(t, b.Db.Where("action = ?", AUDIT_ACTION_UPDATE_HASH).First(&event).Error)
+	assert.Equal(t, 7, event.IncidentID)
+}
@@ -0,0 +1,373 @@
+package brimstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/metrics"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Status of a tracked rotation
+const (
+	ROTATION_PENDING   = "pending"   // password change requested, waiting for the CPM
+	ROTATION_SUCCEEDED = "succeeded" // the CPM changed the password
+	ROTATION_FAILED    = "failed"    // the attempt failed or timed out, requested again
+	ROTATION_ESCALATED = "escalated" // every attempt failed or timed out, needs a human
+)
+
+// SecretManagement.Status values set by the CPM
+const (
+	CPM_STATUS_SUCCESS = "success"
+	CPM_STATUS_FAILURE = "failure"
+)
+
+// default number of rotations returned by the rotations endpoint
+const DEFAULT_ROTATIONS_LIMIT = 100
+
+// AccountRotation - password change requested from PAM, confirmed by polling the account CPM status
+type AccountRotation struct {
+	gorm.Model
+	AuditID          uint   // the rotate audit event, updated with the outcome
+	Safename         string `gorm:"index"`
+	AccountID        string `gorm:"index"`
+	IncidentID       int
+	Status           string `gorm:"index"`
+	Attempts         int
+	RequestedAt      time.Time
+	BaselineModified int    // SecretManagement.LastModifiedTime when the change was requested
+	BaselineStatus   string // SecretManagement.Status when the change was requested
+	LeakedHash       string // hash, or hash prefix, of the password when the change was requested, see RehashAccount
+	CompletedAt      *time.Time
+	Error            string
+}
+
+// lockRotationChecks - claim the rotation check lock so checks never overlap
+func (b Brimstone) lockRotationChecks() (func(), bool) {
+	if b.Scheduler == nil {
+		return func() {}, true
+	}
+	if !b.Scheduler.checkingRotations.TryLock() {
+		return nil, false
+	}
+	return b.Scheduler.checkingRotations.Unlock, true
+}
+
+// setBaseline records the CPM status of the account before the password change is requested;
+// when the account cannot be fetched, only a change after the request time confirms the rotation
+func (r *AccountRotation) setBaseline(ctx context.Context, client *pam.Client) {
+	r.RequestedAt = time.Now()
+	r.BaselineModified = int(r.RequestedAt.Unix())
+	r.BaselineStatus = ""
+	account, err := client.GetAccount(ctx, r.AccountID)
+	if err != nil {
+		log.Printf("ERROR: unable to fetch account, %s, before rotation: %s\n", r.AccountID, err.Error())
+		return
+	}
+	r.BaselineModified = account.SecretManagement.LastModifiedTime
+	r.BaselineStatus = account.SecretManagement.Status
+}
+
+// RequestRotation asks PAM to change the account password now and records the rotate audit
+// event. When rotations are tracked, the rotation stays pending until the CPM status confirms
+// it, see CheckRotations. Returns the recorded audit event.
+func (b Brimstone) RequestRotation(ctx context.Context, client *pam.Client, audit RemediationEvent) (RemediationEvent, error) {
+	tracked := b.RotationPollInterval > 0
+	rotation := AccountRotation{
+		Safename:   audit.Safename,
+		AccountID:  audit.AccountID,
+		IncidentID: audit.IncidentID,
+		Status:     ROTATION_PENDING,
+		Attempts:   1,
+	}
+	if tracked {
+		rotation.setBaseline(ctx, client)
+		rotation.LeakedHash = b.leakedHash(audit)
+	}
+
+	code, err := client.ChangePasswordImmediately(ctx, audit.AccountID)
+	metrics.RecordRotation(audit.Safename, err)
+	audit.Action = AUDIT_ACTION_ROTATE
+	audit.ResponseCode = code
+	audit.Error = errorString(err)
+	if tracked && err == nil {
+		audit.RotationStatus = ROTATION_PENDING
+	}
+	audit = b.RecordRemediation(audit)
+	if err != nil || !tracked || b.DryRun {
+		return audit, err
+	}
+
+	rotation.AuditID = audit.ID
+	result := b.Db.Create(&rotation)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to save rotation of account, %s: %s\n", audit.AccountID, result.Error.Error())
+	}
+	return audit, nil
+}
+
+// rotationOutcome - pending, succeeded or failed with the reason, per the CPM status of the account
+func rotationOutcome(r AccountRotation, sm pam.SecretManagement, now time.Time, timeout time.Duration) (string, string) {
+	changed := sm.LastModifiedTime > r.BaselineModified
+	if sm.Status == CPM_STATUS_SUCCESS && changed {
+		return ROTATION_SUCCEEDED, ""
+	}
+	if sm.Status == CPM_STATUS_FAILURE && (changed || r.BaselineStatus != CPM_STATUS_FAILURE) {
+		return ROTATION_FAILED, "the CPM failed to change the password"
+	}
+	if now.After(r.RequestedAt.Add(timeout)) {
+		return ROTATION_FAILED, fmt.Sprintf("password change not confirmed by the CPM within %s", timeout)
+	}
+	return ROTATION_PENDING, ""
+}
+
+// RotationCheckResult - outcome of a check of the pending rotations
+type RotationCheckResult struct {
+	Checked   int
+	Succeeded int
+	Retried   int
+	Escalated int
+}
+
+// CheckRotations polls the CPM status of the accounts with a pending rotation. A failed or
+// timed out rotation is requested again, up to the max attempts, then escalated: the audit
+// event records the failure and the GG incident, if any, gets a note per the safe write-back.
+func (b Brimstone) CheckRotations(ctx context.Context) (RotationCheckResult, error) {
+	var result RotationCheckResult
+	unlock, ok := b.lockRotationChecks()
+	if !ok {
+		return result, nil
+	}
+	defer unlock()
+
+	var rotations []AccountRotation
+	dbresult := b.Db.Where("status = ?", ROTATION_PENDING).Order("id").Find(&rotations)
+	if dbresult.Error != nil {
+		return result, dbresult.Error
+	}
+	if len(rotations) == 0 {
+		return result, nil
+	}
+
+	client := b.PAMSessions.Client()
+	for i := 0; i < len(rotations); i++ {
+		result.Checked++
+		switch b.checkRotation(ctx, &client, rotations[i]) {
+		case ROTATION_SUCCEEDED:
+			result.Succeeded++
+		case ROTATION_ESCALATED:
+			result.Escalated++
+		case ROTATION_FAILED:
+			result.Retried++
+		}
+	}
+	return result, nil
+}
+
+// checkRotation - checks the rotation and saves its outcome; returns the outcome
+func (b Brimstone) checkRotation(ctx context.Context, client *pam.Client, rotation AccountRotation) string {
+	now := time.Now()
+	timeout := b.RotationTimeout
+
+	var outcome, reason string
+	account, err := client.GetAccount(ctx, rotation.AccountID)
+	if err != nil {
+		log.Printf("ERROR: unable to check rotation of account, %s: %s\n", rotation.AccountID, err.Error())
+		if !now.After(rotation.RequestedAt.Add(timeout)) {
+			return ROTATION_PENDING
+		}
+		outcome, reason = ROTATION_FAILED, fmt.Sprintf("unable to check the password change: %s", err.Error())
+	} else {
+		outcome, reason = rotationOutcome(rotation, account.SecretManagement, now, timeout)
+	}
+
+	switch outcome {
+	case ROTATION_PENDING:
+		return outcome
+	case ROTATION_SUCCEEDED:
+		rotation.Status = ROTATION_SUCCEEDED
+		rotation.CompletedAt = &now
+		rotation.Error = ""
+		log.Printf("INFO: rotation of account, %s, confirmed after %d attempt(s)\n", rotation.AccountID, rotation.Attempts)
+		audit := b.updateRotationAudit(rotation)
+		if b.RehashAfterRotation {
+			err := b.RehashAccount(ctx, client, rotation.Safename, rotation.AccountID, rotation.LeakedHash, audit)
+			if errors.Is(err, ErrHashUnchanged) {
+				outcome = ROTATION_ESCALATED
+				b.escalateRotation(ctx, &rotation, now, err.Error())
+			}
+		}
+	default:
+		maxattempts := b.RotationMaxAttempts
+		if rotation.Attempts < maxattempts {
+			rotation.Attempts++
+			rotation.Error = reason
+			rotation.setBaseline(ctx, client)
+			code, err := client.ChangePasswordImmediately(ctx, rotation.AccountID)
+			metrics.RecordRotation(rotation.Safename, err)
+			if err != nil {
+				// the next check times out and tries again
+				rotation.Error = fmt.Sprintf("%s, (code=%d) %s", reason, code, err.Error())
+			}
+			log.Printf("INFO: retrying rotation of account, %s, attempt %d of %d: %s\n", rotation.AccountID, rotation.Attempts, maxattempts, reason)
+			b.updateRotationAudit(rotation)
+			break
+		}
+		outcome = ROTATION_ESCALATED
+		b.escalateRotation(ctx, &rotation, now, fmt.Sprintf("rotation failed after %d attempt(s): %s", rotation.Attempts, reason))
+	}
+
+	result := b.Db.Save(&rotation)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to save rotation of account, %s: %s\n", rotation.AccountID, result.Error.Error())
+	}
+	if outcome == ROTATION_SUCCEEDED && rotation.IncidentID != 0 {
+		b.completeIncidentRotations(ctx, rotation.IncidentID)
+	}
+	return outcome
+}
+
+// completeIncidentRotations - once the last rotation of every account of the incident succeeded,
+// the incident is marked remediated and the GG incident gets the outcome per the safe write-back
+func (b Brimstone) completeIncidentRotations(ctx context.Context, incidentid int) {
+	var rotations []AccountRotation
+	result := b.Db.Where("incident_id = ?", incidentid).Order("id").Find(&rotations)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to fetch rotations of incident, %d: %s\n", incidentid, result.Error.Error())
+		return
+	}
+
+	// the last rotation of each account, an earlier escalated one was remediated again
+	var accountids []string
+	latest := map[string]AccountRotation{}
+	for i := 0; i < len(rotations); i++ {
+		if _, ok := latest[rotations[i].AccountID]; !ok {
+			accountids = append(accountids, rotations[i].AccountID)
+		}
+		latest[rotations[i].AccountID] = rotations[i]
+	}
+	var audits []RemediationEvent
+	for i := 0; i < len(accountids); i++ {
+		rotation := latest[accountids[i]]
+		if rotation.Status != ROTATION_SUCCEEDED {
+			return
+		}
+		var audit RemediationEvent
+		if rotation.AuditID == 0 || b.Db.First(&audit, rotation.AuditID).Error != nil {
+			continue
+		}
+		audits = append(audits, audit)
+	}
+
+	var incident Incident
+	result = b.Db.Where("incident_id = ?", incidentid).Limit(1).Find(&incident)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to fetch incident, %d: %s\n", incidentid, result.Error.Error())
+	} else {
+		b.MarkIncidentRemediated(incident, audits)
+	}
+	b.WriteBackIncident(ctx, incidentid, audits)
+}
+
+// escalateRotation - the rotation needs a human: the audit event records the reason, the incident,
+// if any, is no longer remediated and the GG incident gets a note per the safe write-back
+func (b Brimstone) escalateRotation(ctx context.Context, rotation *AccountRotation, now time.Time, reason string) {
+	rotation.Status = ROTATION_ESCALATED
+	rotation.CompletedAt = &now
+	rotation.Error = reason
+	log.Printf("ERROR: ESCALATION: safename: %s, account id: %s, incident: %d, %s\n", rotation.Safename, rotation.AccountID, rotation.IncidentID, rotation.Error)
+	audit := b.updateRotationAudit(*rotation)
+	if rotation.IncidentID != 0 {
+		b.clearIncidentRemediated(rotation.IncidentID)
+	}
+	if rotation.IncidentID != 0 && audit.ID != 0 {
+		b.WriteBackIncident(ctx, rotation.IncidentID, []RemediationEvent{audit})
+	}
+}
+
+// updateRotationAudit - the rotate audit event records the rotation status and error
+func (b Brimstone) updateRotationAudit(rotation AccountRotation) RemediationEvent {
+	var audit RemediationEvent
+	if rotation.AuditID == 0 {
+		return audit
+	}
+	result := b.Db.First(&audit, rotation.AuditID)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to fetch audit event, %d: %s\n", rotation.AuditID, result.Error.Error())
+		return RemediationEvent{}
+	}
+	audit.RotationStatus = rotation.Status
+	audit.Error = rotation.Error
+	result = b.Db.Save(&audit)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to update audit event, %d: %s\n", rotation.AuditID, result.Error.Error())
+	}
+	return audit
+}
+
+// RunCheckRotations - maintenance task run by the scheduler
+func (b Brimstone) RunCheckRotations() {
+	result, err := b.CheckRotations(context.Background())
+	if err != nil {
+		log.Printf("ERROR: checking rotations: %s\n", err.Error())
+		return
+	}
+	if result.Succeeded+result.Retried+result.Escalated > 0 {
+		log.Printf("INFO: checked rotations, checked: %d, succeeded: %d, retried: %d, escalated: %d\n", result.Checked, result.Succeeded, result.Retried, result.Escalated)
+	}
+}
+
+func accountRotationToAPI(r AccountRotation) Rotation {
+	rsp := Rotation{
+		Id:          int(r.ID),
+		Safename:    r.Safename,
+		AccountId:   r.AccountID,
+		Status:      r.Status,
+		Attempts:    r.Attempts,
+		RequestedAt: r.RequestedAt,
+		CompletedAt: r.CompletedAt,
+	}
+	if r.IncidentID != 0 {
+		rsp.IncidentId = &r.IncidentID
+	}
+	if r.Error != "" {
+		rsp.Error = &r.Error
+	}
+	return rsp
+}
+
+// RotationsGet - GET /v1/rotations
+func (b Brimstone) RotationsGet(ctx echo.Context, params RotationsGetParams) error {
+	db := b.Db
+
+	limit := DEFAULT_ROTATIONS_LIMIT
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	query := db.Order("created_at desc").Limit(limit)
+	if params.Safename != nil {
+		query = query.Where("safename = ?", *params.Safename)
+	}
+	if params.Status != nil {
+		query = query.Where("status = ?", string(*params.Status))
+	}
+
+	var rotations []AccountRotation
+	result := query.Find(&rotations)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch rotations")
+	}
+
+	rsp := []Rotation{}
+	for i := 0; i < len(rotations); i++ {
+		rsp = append(rsp, accountRotationToAPI(rotations[i]))
+	}
+	return ctx.JSON(200, rsp)
+}
@@ -0,0 +1,270 @@
+package brimstone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// cpmStub - PAM stub whose accounts report the CPM status set by the test
+type cpmStub struct {
+	mu       sync.Mutex
+	status   string
+	modified int
+	changes  int
+	password string
+}
+
+func (s *cpmStub) set(status string, modified int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.modified = modified
+}
+
+func newCPMStub(t *testing.T) (*pam.SessionManager, *cpmStub) {
+	stub := &cpmStub{status: CPM_STATUS_SUCCESS, modified: 1000}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		switch {
+		case r.URL.Path == "/oauth2/platformtoken":
+			w.Write([]byte(`{"token_type":"Bearer","access_token":"pamtoken","expires_in":900}`))
+		case strings.HasSuffix(r.URL.Path, "/Password/Retrieve"):
+			w.Write([]byte(`"` + stub.password + `"`))
+		case strings.HasSuffix(r.URL.Path, "/Change"):
+			stub.changes++
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(pam.Account{
+				ID:               strings.TrimPrefix(r.URL.Path, "/PasswordVault/API/Accounts/"),
+				SecretManagement: pam.SecretManagement{Status: stub.status, LastModifiedTime: stub.modified},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return pam.NewSessionManager(pam.Config{IDTenantURL: server.URL, PCloudURL: server.URL}), stub
+}
+
+func newTrackingBrimstone(t *testing.T) (Brimstone, *cpmStub) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	sessions, stub := newCPMStub(t)
+	b.PAMSessions = sessions
+	b.RotationPollInterval = time.Minute
+	b.RotationTimeout = 10 * time.Minute
+	b.RotationMaxAttempts = 2
+	return b, stub
+}
+
+func TestRotationOutcome(t *testing.T) {
+	now := time.Now()
+	rotation := AccountRotation{RequestedAt: now.Add(-time.Minute), BaselineModified: 1000, BaselineStatus: CPM_STATUS_SUCCESS}
+	tests := []struct {
+		rotation AccountRotation
+		sm       pam.SecretManagement
+		outcome  string
+	}{
+		{rotation, pam.SecretManagement{Status: CPM_STATUS_SUCCESS, LastModifiedTime: 1000}, ROTATION_PENDING},
+		{rotation, pam.SecretManagement{Status: CPM_STATUS_SUCCESS, LastModifiedTime: 1001}, ROTATION_SUCCEEDED},
+		{rotation, pam.SecretManagement{Status: CPM_STATUS_FAILURE, LastModifiedTime: 1000}, ROTATION_FAILED},
+		// an account already failing only fails again once the CPM tried
+		{AccountRotation{RequestedAt: rotation.RequestedAt, BaselineModified: 1000, BaselineStatus: CPM_STATUS_FAILURE}, pam.SecretManagement{Status: CPM_STATUS_FAILURE, LastModifiedTime: 1000}, ROTATION_PENDING},
+		{AccountRotation{RequestedAt: rotation.RequestedAt, BaselineModified: 1000, BaselineStatus: CPM_STATUS_FAILURE}, pam.SecretManagement{Status: CPM_STATUS_FAILURE, LastModifiedTime: 1001}, ROTATION_FAILED},
+		// not confirmed within the timeout
+		{AccountRotation{RequestedAt: now.Add(-time.Hour), BaselineModified: 1000}, pam.SecretManagement{Status: CPM_STATUS_SUCCESS, LastModifiedTime: 1000}, ROTATION_FAILED},
+	}
+	for i := 0; i < len(tests); i++ {
+		outcome, _ := rotationOutcome(tests[i].rotation, tests[i].sm, now, 10*time.Minute)
+		assert.Equal(t, tests[i].outcome, outcome, "test %d", i)
+	}
+}
+
+func TestRequestRotationConfirmed(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	client := b.PAMSessions.Client()
+
+	audit, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Safename: "safe1", AccountID: "1_1"})
+	assert.NoError(t, err)
+	assert.Equal(t, ROTATION_PENDING, audit.RotationStatus)
+	assert.Equal(t, 1, stub.changes)
+
+	var rotation AccountRotation
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Equal(t, ROTATION_PENDING, rotation.Status)
+	assert.Equal(t, 1000, rotation.BaselineModified)
+	assert.Equal(t, audit.ID, rotation.AuditID)
+
+	// the CPM has not changed the password yet
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1}, result)
+
+	stub.set(CPM_STATUS_SUCCESS, 2000)
+	result, err = b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Succeeded: 1}, result)
+
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Equal(t, ROTATION_SUCCEEDED, rotation.Status)
+	assert.NotNil(t, rotation.CompletedAt)
+	assert.NoError(t, b.Db.First(&audit, audit.ID).Error)
+	assert.Equal(t, ROTATION_SUCCEEDED, audit.RotationStatus)
+
+	// nothing left to check
+	result, err = b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{}, result)
+}
+
+func TestCheckRotationsRetryThenEscalate(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	client := b.PAMSessions.Client()
+
+	audit, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Safename: "safe1", AccountID: "1_1"})
+	assert.NoError(t, err)
+
+	// the CPM fails, the rotation is requested again
+	stub.set(CPM_STATUS_FAILURE, 1000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Retried: 1}, result)
+	assert.Equal(t, 2, stub.changes)
+
+	var rotation AccountRotation
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Equal(t, ROTATION_PENDING, rotation.Status)
+	assert.Equal(t, 2, rotation.Attempts)
+	assert.Equal(t, CPM_STATUS_FAILURE, rotation.BaselineStatus)
+
+	// the CPM fails again, the last attempt is escalated
+	stub.set(CPM_STATUS_FAILURE, 1001)
+	result, err = b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Escalated: 1}, result)
+	assert.Equal(t, 2, stub.changes)
+
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Equal(t, ROTATION_ESCALATED, rotation.Status)
+	assert.Contains(t, rotation.Error, "rotation failed after 2 attempt(s)")
+	assert.NoError(t, b.Db.First(&audit, audit.ID).Error)
+	assert.Equal(t, ROTATION_ESCALATED, audit.RotationStatus)
+	assert.Equal(t, rotation.Error, audit.Error)
+}
+
+func TestCheckRotationsCompleteIncident(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	ggstub, ggclient := newGGStub(t)
+	b.GGClient = ggclient
+	b.Db.Create(&SafeWriteBack{Safename: "safe1", Mode: GG_WRITEBACK_RESOLVE})
+	incident := Incident{IncidentID: 5, RemediationStatus: REMEDIATION_STATUS_OPEN}
+	b.Db.Create(&incident)
+	client := b.PAMSessions.Client()
+
+	var remediated []RemediationEvent
+	for _, accountid := range []string{"1_1", "1_2"} {
+		audit, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_GG_WEBHOOK, IncidentID: 5, Safename: "safe1", AccountID: accountid})
+		assert.NoError(t, err)
+		remediated = append(remediated, audit)
+	}
+	b.MarkIncidentRemediated(incident, remediated)
+	b.WriteBackIncident(context.Background(), 5, remediated)
+
+	// pending rotations, the incident is neither remediated nor resolved
+	assert.NoError(t, b.Db.First(&incident, incident.ID).Error)
+	assert.Nil(t, incident.RemediatedAt)
+	assert.Equal(t, []string{"POST /v1/incidents/secrets/5/notes"}, ggstub.requests)
+
+	// the CPM confirms both rotations
+	ggstub.requests = nil
+	stub.set(CPM_STATUS_SUCCESS, 2000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 2, Succeeded: 2}, result)
+	assert.NoError(t, b.Db.First(&incident, incident.ID).Error)
+	assert.NotNil(t, incident.RemediatedAt)
+	assert.Equal(t, []string{
+		"POST /v1/incidents/secrets/5/notes",
+		"POST /v1/incidents/secrets/5/resolve",
+	}, ggstub.requests)
+}
+
+func TestCheckRotationsEscalatedIncidentReconciled(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	b.RotationMaxAttempts = 1
+	remediatedat := time.Now()
+	incident := Incident{IncidentID: 5, RemediationStatus: REMEDIATION_STATUS_OPEN, RemediatedAt: &remediatedat}
+	b.Db.Create(&incident)
+	client := b.PAMSessions.Client()
+
+	_, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_GG_WEBHOOK, IncidentID: 5, Safename: "safe1", AccountID: "1_1"})
+	assert.NoError(t, err)
+
+	stub.set(CPM_STATUS_FAILURE, 2000)
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Escalated: 1}, result)
+
+	// reconciliation remediates the incident again
+	var escalated Incident
+	assert.NoError(t, b.Db.First(&escalated, incident.ID).Error)
+	assert.Nil(t, escalated.RemediatedAt)
+}
+
+func TestCheckRotationsTimeout(t *testing.T) {
+	b, stub := newTrackingBrimstone(t)
+	b.RotationMaxAttempts = 1
+	b.Db.Create(&AccountRotation{Safename: "safe1", AccountID: "1_1", Status: ROTATION_PENDING, Attempts: 1, RequestedAt: time.Now().Add(-time.Hour), BaselineModified: 1000})
+
+	result, err := b.CheckRotations(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, RotationCheckResult{Checked: 1, Escalated: 1}, result)
+	assert.Equal(t, 0, stub.changes)
+
+	var rotation AccountRotation
+	assert.NoError(t, b.Db.First(&rotation).Error)
+	assert.Contains(t, rotation.Error, "not confirmed by the CPM within 10m0s")
+}
+
+func TestRequestRotationUntracked(t *testing.T) {
+	b, _ := newTrackingBrimstone(t)
+	b.RotationPollInterval = 0
+	client := b.PAMSessions.Client()
+
+	audit, err := b.RequestRotation(context.Background(), &client, RemediationEvent{Source: AUDIT_SOURCE_HMSL_SCAN, Safename: "safe1", AccountID: "1_1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", audit.RotationStatus)
+	var count int64
+	b.Db.Model(&AccountRotation{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRotationsGet(t *testing.T) {
+	b, _ := newTrackingBrimstone(t)
+	b.Db.Create(&[]AccountRotation{
+		{Safename: "safe1", AccountID: "1_1", Status: ROTATION_SUCCEEDED, Attempts: 1, RequestedAt: time.Now()},
+		{Safename: "safe1", AccountID: "1_2", Status: ROTATION_ESCALATED, Attempts: 3, RequestedAt: time.Now(), Error: "rotation failed after 3 attempt(s)"},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/v1/rotations?status=escalated", nil)
+	rec := httptest.NewRecorder()
+	status := Escalated
+	assert.NoError(t, b.RotationsGet(e.NewContext(req, rec), RotationsGetParams{Status: &status}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rotations []Rotation
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rotations))
+	assert.Len(t, rotations, 1)
+	assert.Equal(t, "1_2", rotations[0].AccountId)
+	assert.Equal(t, 3, rotations[0].Attempts)
+}
@@ -0,0 +1,481 @@
+package brimstone
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/metrics"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Scan job status values; per-safe progress uses the same values
+const (
+	JOB_STATUS_QUEUED    = "queued"
+	JOB_STATUS_RUNNING   = "running"
+	JOB_STATUS_SUCCEEDED = "succeeded"
+	JOB_STATUS_FAILED    = "failed"
+)
+
+// Scan job modes
+const (
+	SCAN_MODE_FULL   = "full"
+	SCAN_MODE_PREFIX = "prefix"
+)
+
+// default number of jobs returned by the job list endpoint
+const DEFAULT_JOB_LIST_LIMIT = 100
+
+// Asynchronous leak scan requested through the api
+type ScanJob struct {
+	gorm.Model
+	Mode       string
+	Safenames  string // comma separated safe filter, empty means all safes
+	Status     string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	Error      string
+	Safes      []ScanJobSafe
+	Leaks      []ScanJobLeak
+}
+
+// Progress and stats of a safe in a scan job
+type ScanJobSafe struct {
+	gorm.Model
+	ScanJobID     uint
+	Safename      string
+	Status        string
+	TotalCount    int
+	InvalidCount  int
+	DistinctCount int
+	SendCount     int
+	LeakCount     int
+	RotatedCount  int
+	FailedCount   int
+	Message       string
+}
+
+// Leaked hash found by a scan job and the outcome of rotating its accounts
+type ScanJobLeak struct {
+	gorm.Model
+	ScanJobID uint
+	Safename  string
+	Hash      string
+	Count     int
+	Location  string
+	Rotated   bool
+	Error     string
+}
+
+// SafenameList returns the safe filter of the job
+func (j ScanJob) SafenameList() []string {
+	if j.Safenames == "" {
+		return nil
+	}
+	return strings.Split(j.Safenames, ",")
+}
+
+// EnqueueScanJob saves a new scan job and wakes up the job worker
+func (b Brimstone) EnqueueScanJob(mode string, safenames []string) (*ScanJob, error) {
+	db := b.Db
+
+	if mode != SCAN_MODE_FULL && mode != SCAN_MODE_PREFIX {
+		return nil, fmt.Errorf("unsupported scan mode: %s", mode)
+	}
+	for i := 0; i < len(safenames); i++ {
+		if safenames[i] == "" || strings.Contains(safenames[i], ",") {
+			return nil, fmt.Errorf("invalid safename: %q", safenames[i])
+		}
+	}
+
+	job := ScanJob{
+		Mode:      mode,
+		Safenames: strings.Join(safenames, ","),
+		Status:    JOB_STATUS_QUEUED,
+	}
+	result := db.Create(&job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	b.Scheduler.wakeJobs()
+	return &job, nil
+}
+
+// wakeJobs signals the job worker that there are queued jobs, without blocking
+func (s *Scheduler) wakeJobs() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.jobs <- struct{}{}:
+	default:
+	}
+}
+
+// startScanJobs requeues jobs interrupted by a restart and starts the job worker
+func (b Brimstone) startScanJobs() error {
+	db := b.Db
+
+	result := db.Model(&ScanJob{}).Where("status = ?", JOB_STATUS_RUNNING).Update("status", JOB_STATUS_QUEUED)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("INFO: requeued %d interrupted scan job(s)\n", result.RowsAffected)
+	}
+
+	go b.runScanJobs()
+	b.Scheduler.wakeJobs()
+	return nil
+}
+
+// runScanJobs - job worker; runs the queued jobs oldest first, then waits to be woken up
+func (b Brimstone) runScanJobs() {
+	db := b.Db
+
+	for range b.Scheduler.jobs {
+		for {
+			var job ScanJob
+			result := db.Where("status = ?", JOB_STATUS_QUEUED).Order("id").Limit(1).Find(&job)
+			if result.Error != nil {
+				log.Printf("ERROR: unable to fetch queued scan jobs: %s\n", result.Error.Error())
+				break
+			}
+			if result.RowsAffected == 0 {
+				break
+			}
+			b.RunScanJob(&job)
+		}
+	}
+}
+
+// RunScanJob processes each safe of the job, saving progress as it goes so an interrupted
+// job resumes with the safes that were not completed
+func (b Brimstone) RunScanJob(job *ScanJob) {
+	db := b.Db
+	contextctx := context.Background()
+	start := time.Now()
+	defer func() {
+		metrics.ScanDuration.WithLabelValues(job.Mode).Observe(time.Since(start).Seconds())
+	}()
+
+	// wait for any running scan so scans never overlap
+	if b.Scheduler != nil {
+		b.Scheduler.scanning.Lock()
+		defer b.Scheduler.scanning.Unlock()
+	}
+
+	if job.StartedAt == nil {
+		started := time.Now()
+		job.StartedAt = &started
+	}
+	job.Status = JOB_STATUS_RUNNING
+	db.Save(job)
+
+	safes, err := b.scanJobSafes(job)
+	if err != nil {
+		b.finishScanJob(job, err)
+		return
+	}
+
+	// hashes already rotated by this job; a hash can be in more than one safe
+	rotated := make(map[string]bool)
+	var leaks []ScanJobLeak
+	db.Where("scan_job_id = ?", job.ID).Find(&leaks)
+	for i := 0; i < len(leaks); i++ {
+		rotated[leaks[i].Hash] = true
+	}
+
+	var failed []string
+	for i := 0; i < len(safes); i++ {
+		if safes[i].Status == JOB_STATUS_SUCCEEDED {
+			continue
+		}
+		safes[i].Status = JOB_STATUS_RUNNING
+		db.Save(&safes[i])
+
+		err := b.runScanJobSafe(contextctx, job, &safes[i], rotated)
+		if err != nil {
+			safes[i].Status = JOB_STATUS_FAILED
+			safes[i].Message = err.Error()
+		} else if safes[i].FailedCount > 0 {
+			safes[i].Status = JOB_STATUS_FAILED
+		} else {
+			safes[i].Status = JOB_STATUS_SUCCEEDED
+		}
+		if safes[i].Status == JOB_STATUS_FAILED {
+			failed = append(failed, safes[i].Safename)
+		}
+		db.Save(&safes[i])
+	}
+
+	if len(failed) > 0 {
+		err = fmt.Errorf("scan failed for safe(s): %s", strings.Join(failed, ", "))
+	}
+	b.finishScanJob(job, err)
+}
+
+// scanJobSafes returns the per-safe progress records of the job, creating them on the first run
+func (b Brimstone) scanJobSafes(job *ScanJob) ([]ScanJobSafe, error) {
+	db := b.Db
+
+	var safes []ScanJobSafe
+	result := db.Where("scan_job_id = ?", job.ID).Order("safename").Find(&safes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(safes) > 0 {
+		return safes, nil
+	}
+
+	safenames := job.SafenameList()
+	if len(safenames) == 0 {
+		var err error
+		safenames, err = b.FetchSafenames()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(safenames) == 0 {
+		return safes, nil
+	}
+	for i := 0; i < len(safenames); i++ {
+		safes = append(safes, ScanJobSafe{
+			ScanJobID: job.ID,
+			Safename:  safenames[i],
+			Status:    JOB_STATUS_QUEUED,
+		})
+	}
+	result = db.CreateInBatches(safes, 100)
+	return safes, result.Error
+}
+
+// runScanJobSafe sends the hashes of one safe to HMSL and rotates the leaked accounts
+func (b Brimstone) runScanJobSafe(contextctx context.Context, job *ScanJob, safe *ScanJobSafe, rotated map[string]bool) error {
+	db := b.Db
+
+	stats := SendHashesStats{
+		SafeStats: &[]SafeHashStats{},
+	}
+
+	var leaked []hmsl.SecretResponse
+	var err error
+	if job.Mode == SCAN_MODE_PREFIX {
+		leaked, err = b.SendSafePrefixes(contextctx, safe.Safename, &stats)
+	} else {
+		var validationerrs []error
+		leaked, validationerrs, err = b.SendSafeFullHashes(contextctx, safe.Safename, &stats)
+		var messages []string
+		for i := 0; i < len(validationerrs); i++ {
+			messages = append(messages, validationerrs[i].Error())
+		}
+		safe.Message = strings.Join(messages, "; ")
+	}
+
+	if len(*stats.SafeStats) > 0 {
+		safestats := (*stats.SafeStats)[len(*stats.SafeStats)-1]
+		safe.TotalCount = safestats.TotalCount
+		safe.InvalidCount = safestats.InvalidCount
+		safe.DistinctCount = safestats.DistinctCount
+		if safestats.Message != nil {
+			safe.Message = strings.Join(*safestats.Message, "; ")
+		}
+	}
+	safe.SendCount = stats.SendCount
+	if err != nil {
+		return err
+	}
+
+	safe.LeakCount = len(leaked)
+	for i := 0; i < len(leaked); i++ {
+		leak := ScanJobLeak{
+			ScanJobID: job.ID,
+			Safename:  safe.Safename,
+			Hash:      leaked[i].Hash,
+			Count:     leaked[i].Count,
+		}
+		if rotated[leaked[i].Hash] {
+			leak.Error = "already rotated by this job"
+		} else if b.DryRun {
+			_, planErr := b.PlanLeakedHash(&leaked[i])
+			rotated[leaked[i].Hash] = true
+			leak.Error = "dry run, password not changed"
+			if planErr != nil {
+				leak.Error = planErr.Error()
+			}
+		} else {
+			rotateErr := b.RotateLeakedHash(contextctx, &leaked[i])
+			rotated[leaked[i].Hash] = true
+			if rotateErr != nil {
+				leak.Error = rotateErr.Error()
+				safe.FailedCount++
+			} else {
+				leak.Rotated = true
+				safe.RotatedCount++
+			}
+		}
+		if leaked[i].Location != nil {
+			leak.Location = leaked[i].Location.U
+		}
+		if result := db.Create(&leak); result.Error != nil {
+			log.Printf("ERROR: unable to save scan job leak: %s\n", result.Error.Error())
+		}
+	}
+	return nil
+}
+
+// finishScanJob records the final status of the job
+func (b Brimstone) finishScanJob(job *ScanJob, err error) {
+	db := b.Db
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Status = JOB_STATUS_SUCCEEDED
+	job.Error = ""
+	if err != nil {
+		job.Status = JOB_STATUS_FAILED
+		job.Error = err.Error()
+	}
+	log.Printf("INFO: scan job id, %d, %s\n", job.ID, job.Status)
+	if result := db.Save(job); result.Error != nil {
+		log.Printf("ERROR: unable to save scan job: %s\n", result.Error.Error())
+	}
+}
+
+func scanJobToAPI(job ScanJob) ScanJobStatus {
+	rsp := ScanJobStatus{
+		Id:         int(job.ID),
+		Mode:       job.Mode,
+		Status:     job.Status,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if safenames := job.SafenameList(); len(safenames) > 0 {
+		rsp.Safenames = &safenames
+	}
+	if job.Error != "" {
+		rsp.Error = &job.Error
+	}
+	return rsp
+}
+
+func scanJobSafeToAPI(s ScanJobSafe) ScanJobSafeStatus {
+	rsp := ScanJobSafeStatus{
+		Safename:      s.Safename,
+		Status:        s.Status,
+		Totalcount:    &s.TotalCount,
+		Invalidcount:  &s.InvalidCount,
+		Distinctcount: &s.DistinctCount,
+		Sendcount:     &s.SendCount,
+		Leakcount:     &s.LeakCount,
+		Rotatedcount:  &s.RotatedCount,
+		Failedcount:   &s.FailedCount,
+	}
+	if s.Message != "" {
+		rsp.Message = &s.Message
+	}
+	return rsp
+}
+
+func scanJobLeakToAPI(l ScanJobLeak) ScanJobLeakStatus {
+	rsp := ScanJobLeakStatus{
+		Safename: l.Safename,
+		Hash:     l.Hash,
+		Count:    &l.Count,
+		Rotated:  l.Rotated,
+	}
+	if l.Location != "" {
+		rsp.Location = &l.Location
+	}
+	if l.Error != "" {
+		rsp.Error = &l.Error
+	}
+	return rsp
+}
+
+// ScanJobsPost - POST /v2/scans
+func (b Brimstone) ScanJobsPost(ctx echo.Context) error {
+	var req ScanJobRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for ScanJobRequest")
+	}
+
+	mode := SCAN_MODE_FULL
+	if req.Mode != nil {
+		mode = string(*req.Mode)
+	}
+	var safenames []string
+	if req.Safenames != nil {
+		safenames = *req.Safenames
+	}
+
+	job, err := b.EnqueueScanJob(mode, safenames)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+	return ctx.JSON(http.StatusAccepted, scanJobToAPI(*job))
+}
+
+// ScanJobGet - GET /v2/scans/{id}
+func (b Brimstone) ScanJobGet(ctx echo.Context, id int) error {
+	db := b.Db
+
+	var job ScanJob
+	result := db.Preload("Safes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("safename")
+	}).Preload("Leaks").Limit(1).Find(&job, id)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch scan job")
+	}
+	if result.RowsAffected == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("scan job id, %d, not found", id))
+	}
+
+	rsp := scanJobToAPI(job)
+	safes := []ScanJobSafeStatus{}
+	for i := 0; i < len(job.Safes); i++ {
+		safes = append(safes, scanJobSafeToAPI(job.Safes[i]))
+	}
+	rsp.Safes = &safes
+	leaks := []ScanJobLeakStatus{}
+	for i := 0; i < len(job.Leaks); i++ {
+		leaks = append(leaks, scanJobLeakToAPI(job.Leaks[i]))
+	}
+	rsp.Leaks = &leaks
+
+	return ctx.JSON(200, rsp)
+}
+
+// ScanJobsGet - GET /v2/scans
+func (b Brimstone) ScanJobsGet(ctx echo.Context, params ScanJobsGetParams) error {
+	db := b.Db
+
+	limit := DEFAULT_JOB_LIST_LIMIT
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	query := db.Order("id desc").Limit(limit)
+	if params.Status != nil {
+		query = query.Where("status = ?", *params.Status)
+	}
+
+	var jobs []ScanJob
+	result := query.Find(&jobs)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch scan jobs")
+	}
+
+	rsp := []ScanJobStatus{}
+	for i := 0; i < len(jobs); i++ {
+		rsp = append(rsp, scanJobToAPI(jobs[i]))
+	}
+	return ctx.JSON(200, rsp)
+}
@@ -0,0 +1,67 @@
+package brimstone
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hmsl "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/hasmysecretleaked"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestBrimstone(t *testing.T, hmslurl string) Brimstone {
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_foreign_keys=on", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	assert.NoError(t, err)
+
+	hmslclient, err := hmsl.NewClientWithResponses(hmslurl)
+	assert.NoError(t, err)
+
+	b := Brimstone{
+		Db:         db,
+		HMSLClient: hmslclient,
+		Scheduler:  NewScheduler(),
+	}
+	assert.NoError(t, b.InitializeDb())
+	return b
+}
+
+func TestRunScanJobNoLeaks(t *testing.T) {
+	hmslstub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"secrets": []}`))
+	}))
+	defer hmslstub.Close()
+
+	b := newTestBrimstone(t, hmslstub.URL)
+	b.Db.Create(&[]SafeHash{
+		{Safename: "safe1", Name: "1_1", Hash: "aaaa1"},
+		{Safename: "safe1", Name: "1_2", Hash: "aaaa1"},
+		{Safename: "safe2", Name: "2_1", Hash: "bbbb2"},
+	})
+
+	job, err := b.EnqueueScanJob(SCAN_MODE_FULL, []string{"safe1"})
+	assert.NoError(t, err)
+	b.RunScanJob(job)
+
+	var saved ScanJob
+	b.Db.Preload("Safes").Preload("Leaks").First(&saved, job.ID)
+	assert.Equal(t, JOB_STATUS_SUCCEEDED, saved.Status)
+	assert.NotNil(t, saved.FinishedAt)
+	assert.Len(t, saved.Leaks, 0)
+	assert.Len(t, saved.Safes, 1)
+	assert.Equal(t, "safe1", saved.Safes[0].Safename)
+	assert.Equal(t, JOB_STATUS_SUCCEEDED, saved.Safes[0].Status)
+	assert.Equal(t, 2, saved.Safes[0].TotalCount)
+	assert.Equal(t, 1, saved.Safes[0].DistinctCount)
+}
+
+func TestEnqueueScanJobInvalidMode(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+
+	_, err := b.EnqueueScanJob("bogus", nil)
+	assert.Error(t, err)
+}
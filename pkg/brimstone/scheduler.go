@@ -0,0 +1,416 @@
+package brimstone
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+// Scan run status values
+const (
+	SCAN_STATUS_RUNNING   = "running"
+	SCAN_STATUS_SUCCEEDED = "succeeded"
+	SCAN_STATUS_FAILED    = "failed"
+	SCAN_STATUS_SKIPPED   = "skipped"
+)
+
+// default number of runs returned by the run history endpoint
+const DEFAULT_RUN_HISTORY_LIMIT = 100
+
+// Schedule on which full hashes of a safe (or all safes when Safename is empty) are sent to HMSL
+type ScanSchedule struct {
+	gorm.Model
+	Safename  string
+	Cron      string
+	Interval  string
+	Enabled   bool
+	LastRunAt *time.Time
+}
+
+// History of each scheduled scan
+type ScanRun struct {
+	gorm.Model
+	ScheduleID   uint
+	Safename     string
+	Status       string
+	StartedAt    time.Time
+	FinishedAt   *time.Time
+	SendCount    int
+	LeakCount    int
+	RotatedCount int
+	FailedCount  int
+	Error        string
+}
+
+// Scheduler runs the saved scan schedules and queued scan jobs, and makes sure only one
+// leak scan runs at a time
+type Scheduler struct {
+	cron     *cron.Cron
+	mu       sync.Mutex
+	entries  map[uint]cron.EntryID
+	scanning sync.Mutex
+	jobs     chan struct{}
+
+	reconciling       sync.Mutex
+	checkingRotations sync.Mutex
+}
+
+func NewScheduler() *Scheduler {
+	scheduler := Scheduler{
+		cron:    cron.New(),
+		entries: make(map[uint]cron.EntryID),
+		jobs:    make(chan struct{}, 1),
+	}
+	return &scheduler
+}
+
+// Spec returns the cron spec for the schedule; an interval is converted to an "@every" spec
+func (s ScanSchedule) Spec() (string, error) {
+	if s.Cron != "" && s.Interval != "" {
+		return "", fmt.Errorf("set either cron or interval, not both")
+	}
+	if s.Interval != "" {
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			return "", fmt.Errorf("invalid interval, %s: %s", s.Interval, err.Error())
+		}
+		if d < time.Minute {
+			return "", fmt.Errorf("interval must be at least 1m")
+		}
+		return fmt.Sprintf("@every %s", d), nil
+	}
+	if s.Cron == "" {
+		return "", fmt.Errorf("cron or interval is required")
+	}
+	if _, err := cron.ParseStandard(s.Cron); err != nil {
+		return "", fmt.Errorf("invalid cron expression, %s: %s", s.Cron, err.Error())
+	}
+	return s.Cron, nil
+}
+
+// lockScan - claim the scan lock so scans never overlap; returns false if a scan is already running
+func (b Brimstone) lockScan() (func(), bool) {
+	if b.Scheduler == nil {
+		return func() {}, true
+	}
+	if !b.Scheduler.scanning.TryLock() {
+		return nil, false
+	}
+	return b.Scheduler.scanning.Unlock, true
+}
+
+// StartScheduler loads the saved scan schedules and starts running them along with the
+// queued scan jobs and the hash pruning maintenance task
+func (b Brimstone) StartScheduler() error {
+	if b.Scheduler == nil {
+		return fmt.Errorf("scheduler is not configured")
+	}
+	err := b.LoadSchedules()
+	if err != nil {
+		return err
+	}
+	err = b.startScanJobs()
+	if err != nil {
+		return err
+	}
+	if b.PruneInterval > 0 {
+		_, err = b.Scheduler.cron.AddFunc(fmt.Sprintf("@every %s", b.PruneInterval), b.RunPruneSafeHashes)
+		if err != nil {
+			return fmt.Errorf("unable to schedule hash pruning: %s", err.Error())
+		}
+	}
+	if b.ReconcileInterval > 0 {
+		_, err = b.Scheduler.cron.AddFunc(fmt.Sprintf("@every %s", b.ReconcileInterval), b.RunReconcileIncidents)
+		if err != nil {
+			return fmt.Errorf("unable to schedule GG incident reconciliation: %s", err.Error())
+		}
+	}
+	if b.RotationPollInterval > 0 {
+		_, err = b.Scheduler.cron.AddFunc(fmt.Sprintf("@every %s", b.RotationPollInterval), b.RunCheckRotations)
+		if err != nil {
+			return fmt.Errorf("unable to schedule rotation checks: %s", err.Error())
+		}
+	}
+	if b.PruneInterval > 0 && b.WebhookMaxSkew > 0 {
+		_, err = b.Scheduler.cron.AddFunc(fmt.Sprintf("@every %s", b.PruneInterval), b.RunPruneWebhookDeliveries)
+		if err != nil {
+			return fmt.Errorf("unable to schedule webhook delivery pruning: %s", err.Error())
+		}
+	}
+	b.Scheduler.cron.Start()
+	return nil
+}
+
+// LoadSchedules (re)registers every enabled schedule from the db with the scheduler
+func (b Brimstone) LoadSchedules() error {
+	db := b.Db
+	s := b.Scheduler
+	if s == nil {
+		return nil
+	}
+
+	var schedules []ScanSchedule
+	result := db.Where("enabled = ?", true).Find(&schedules)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, entryid := range s.entries {
+		s.cron.Remove(entryid)
+		delete(s.entries, id)
+	}
+
+	for i := 0; i < len(schedules); i++ {
+		schedule := schedules[i]
+		spec, err := schedule.Spec()
+		if err != nil {
+			log.Printf("ERROR: skipping scan schedule id, %d: %s\n", schedule.ID, err.Error())
+			continue
+		}
+		entryid, err := s.cron.AddFunc(spec, func() { b.RunScheduledScan(schedule) })
+		if err != nil {
+			log.Printf("ERROR: unable to add scan schedule id, %d: %s\n", schedule.ID, err.Error())
+			continue
+		}
+		s.entries[schedule.ID] = entryid
+	}
+	return nil
+}
+
+// nextRun returns the next time the schedule will run, if it is registered with the scheduler
+func (s *Scheduler) nextRun(id uint) *time.Time {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entryid, ok := s.entries[id]
+	if !ok {
+		return nil
+	}
+	next := s.cron.Entry(entryid).Next
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// RunScheduledScan sends the full hashes for the scheduled safe(s) to HMSL, rotates leaked
+// accounts and records the outcome of the run
+func (b Brimstone) RunScheduledScan(schedule ScanSchedule) {
+	db := b.Db
+
+	run := ScanRun{
+		ScheduleID: schedule.ID,
+		Safename:   schedule.Safename,
+		Status:     SCAN_STATUS_RUNNING,
+		StartedAt:  time.Now(),
+	}
+
+	unlock, ok := b.lockScan()
+	if !ok {
+		finished := time.Now()
+		run.Status = SCAN_STATUS_SKIPPED
+		run.FinishedAt = &finished
+		run.Error = "previous scan still running"
+		log.Printf("INFO: scan schedule id, %d, skipped: %s\n", schedule.ID, run.Error)
+		if result := db.Create(&run); result.Error != nil {
+			log.Printf("ERROR: unable to save scan run: %s\n", result.Error.Error())
+		}
+		return
+	}
+	defer unlock()
+
+	if result := db.Create(&run); result.Error != nil {
+		log.Printf("ERROR: unable to save scan run: %s\n", result.Error.Error())
+	}
+
+	var safenames []string
+	if schedule.Safename != "" {
+		safenames = []string{schedule.Safename}
+	}
+	scanresult, err := b.ScanFullHashes(context.Background(), safenames)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	run.Status = SCAN_STATUS_SUCCEEDED
+	if scanresult != nil {
+		run.SendCount = scanresult.SendCount
+		run.LeakCount = len(scanresult.Responses)
+		run.RotatedCount = scanresult.RotatedCount
+		run.FailedCount = scanresult.FailedCount
+	}
+	if err != nil {
+		run.Status = SCAN_STATUS_FAILED
+		run.Error = err.Error()
+	} else if run.FailedCount > 0 {
+		run.Status = SCAN_STATUS_FAILED
+		run.Error = fmt.Sprintf("%d password change(s) failed", run.FailedCount)
+	}
+	log.Printf("INFO: scan schedule id, %d, %s: sent=%d, leaked=%d, rotated=%d, failed=%d\n", schedule.ID, run.Status, run.SendCount, run.LeakCount, run.RotatedCount, run.FailedCount)
+
+	if result := db.Save(&run); result.Error != nil {
+		log.Printf("ERROR: unable to save scan run: %s\n", result.Error.Error())
+	}
+	db.Model(&ScanSchedule{}).Where("id = ?", schedule.ID).Update("last_run_at", run.StartedAt)
+}
+
+func (b Brimstone) scheduleToAPI(s ScanSchedule) Schedule {
+	id := int(s.ID)
+	schedule := Schedule{
+		Id:       &id,
+		Safename: &s.Safename,
+		Enabled:  &s.Enabled,
+		LastRun:  s.LastRunAt,
+		NextRun:  b.Scheduler.nextRun(s.ID),
+	}
+	if s.Cron != "" {
+		schedule.Cron = &s.Cron
+	}
+	if s.Interval != "" {
+		schedule.Interval = &s.Interval
+	}
+	return schedule
+}
+
+func scanRunToAPI(r ScanRun) ScheduleRun {
+	scheduleid := int(r.ScheduleID)
+	run := ScheduleRun{
+		Id:           int(r.ID),
+		ScheduleId:   &scheduleid,
+		Safename:     &r.Safename,
+		Status:       r.Status,
+		StartedAt:    r.StartedAt,
+		FinishedAt:   r.FinishedAt,
+		Sendcount:    &r.SendCount,
+		Leakcount:    &r.LeakCount,
+		Rotatedcount: &r.RotatedCount,
+		Failedcount:  &r.FailedCount,
+	}
+	if r.Error != "" {
+		run.Error = &r.Error
+	}
+	return run
+}
+
+// SchedulesGet - GET /v1/schedules
+func (b Brimstone) SchedulesGet(ctx echo.Context) error {
+	db := b.Db
+
+	var schedules []ScanSchedule
+	result := db.Order("id").Find(&schedules)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch scan schedules")
+	}
+
+	rsp := []Schedule{}
+	for i := 0; i < len(schedules); i++ {
+		rsp = append(rsp, b.scheduleToAPI(schedules[i]))
+	}
+	return ctx.JSON(200, rsp)
+}
+
+// SchedulesPost - POST /v1/schedules
+func (b Brimstone) SchedulesPost(ctx echo.Context) error {
+	db := b.Db
+
+	var req Schedule
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for Schedule")
+	}
+
+	var schedule ScanSchedule
+	if req.Id != nil {
+		result := db.First(&schedule, *req.Id)
+		if result.Error != nil {
+			return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("scan schedule id, %d, not found", *req.Id))
+		}
+	} else {
+		schedule.Enabled = true
+	}
+	if req.Safename != nil {
+		schedule.Safename = *req.Safename
+	}
+	if req.Cron != nil || req.Interval != nil {
+		schedule.Cron = ""
+		schedule.Interval = ""
+	}
+	if req.Cron != nil {
+		schedule.Cron = *req.Cron
+	}
+	if req.Interval != nil {
+		schedule.Interval = *req.Interval
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+
+	if _, err := schedule.Spec(); err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result := db.Save(&schedule)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to save scan schedule")
+	}
+	if err := b.LoadSchedules(); err != nil {
+		log.Printf("ERROR: unable to reload scan schedules: %s\n", err.Error())
+	}
+
+	return ctx.JSON(200, b.scheduleToAPI(schedule))
+}
+
+// ScheduleDelete - DELETE /v1/schedules/{id}
+func (b Brimstone) ScheduleDelete(ctx echo.Context, id int) error {
+	db := b.Db
+
+	result := db.Delete(&ScanSchedule{}, id)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to delete scan schedule")
+	}
+	if result.RowsAffected == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("scan schedule id, %d, not found", id))
+	}
+	if err := b.LoadSchedules(); err != nil {
+		log.Printf("ERROR: unable to reload scan schedules: %s\n", err.Error())
+	}
+
+	return ctx.JSON(200, "deleted")
+}
+
+// ScheduleRunsGet - GET /v1/schedules/runs
+func (b Brimstone) ScheduleRunsGet(ctx echo.Context, params ScheduleRunsGetParams) error {
+	db := b.Db
+
+	limit := DEFAULT_RUN_HISTORY_LIMIT
+	if params.Limit != nil && *params.Limit > 0 {
+		limit = *params.Limit
+	}
+
+	query := db.Order("started_at desc").Limit(limit)
+	if params.Safename != nil {
+		query = query.Where("safename = ?", *params.Safename)
+	}
+
+	var runs []ScanRun
+	result := query.Find(&runs)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch scan runs")
+	}
+
+	rsp := []ScheduleRun{}
+	for i := 0; i < len(runs); i++ {
+		rsp = append(rsp, scanRunToAPI(runs[i]))
+	}
+	return ctx.JSON(200, rsp)
+}
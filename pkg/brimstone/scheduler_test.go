@@ -0,0 +1,35 @@
+package brimstone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanScheduleSpecInterval(t *testing.T) {
+	s := ScanSchedule{Interval: "6h"}
+
+	spec, err := s.Spec()
+	assert.NoError(t, err)
+	assert.Equal(t, "@every 6h0m0s", spec)
+}
+
+func TestScanScheduleSpecCron(t *testing.T) {
+	s := ScanSchedule{Cron: "0 2 * * *"}
+
+	spec, err := s.Spec()
+	assert.NoError(t, err)
+	assert.Equal(t, "0 2 * * *", spec)
+}
+
+func TestScanScheduleSpecInvalid(t *testing.T) {
+	for _, s := range []ScanSchedule{
+		{},
+		{Cron: "0 2 * * *", Interval: "6h"},
+		{Cron: "not a cron"},
+		{Interval: "10s"},
+	} {
+		_, err := s.Spec()
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,68 @@
+package brimstone
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookDelivery - GG webhook deliveries already handled, used to acknowledge duplicate and
+// replayed deliveries without running the remediation again
+type WebhookDelivery struct {
+	gorm.Model
+	Signature  string `gorm:"uniqueIndex:idx_webhook_delivery"`
+	IncidentID int    `gorm:"uniqueIndex:idx_webhook_delivery"`
+}
+
+// ClaimWebhookDelivery records the delivery; returns false when the same signature and incident
+// were already delivered. In dry-run mode the delivery is only looked up, never recorded.
+func (b Brimstone) ClaimWebhookDelivery(signature string, incidentid int) (WebhookDelivery, bool, error) {
+	db := b.Db
+	delivery := WebhookDelivery{
+		Signature:  signature,
+		IncidentID: incidentid,
+	}
+
+	if b.DryRun {
+		var count int64
+		result := db.Model(&WebhookDelivery{}).Where("signature = ? AND incident_id = ?", signature, incidentid).Count(&count)
+		return delivery, count == 0, result.Error
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	if result.Error != nil {
+		return delivery, false, result.Error
+	}
+	return delivery, result.RowsAffected == 1, nil
+}
+
+// ReleaseWebhookDelivery forgets a claimed delivery so a GG retry of a failed remediation is not
+// treated as a duplicate
+func (b Brimstone) ReleaseWebhookDelivery(delivery WebhookDelivery) {
+	if b.DryRun || delivery.ID == 0 {
+		return
+	}
+	result := b.Db.Unscoped().Delete(&delivery)
+	if result.Error != nil {
+		log.Printf("ERROR: unable to release webhook delivery for incident, %d: %s\n", delivery.IncidentID, result.Error.Error())
+	}
+}
+
+// PruneWebhookDeliveries removes the deliveries received before the given time
+func (b Brimstone) PruneWebhookDeliveries(before time.Time) (int64, error) {
+	result := b.Db.Unscoped().Where("created_at < ?", before).Delete(&WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
+
+// RunPruneWebhookDeliveries - maintenance task run by the scheduler; deliveries older than twice the
+// max skew can no longer pass the timestamp check, so they are not needed to detect replays
+func (b Brimstone) RunPruneWebhookDeliveries() {
+	count, err := b.PruneWebhookDeliveries(time.Now().Add(-2 * b.WebhookMaxSkew))
+	if err != nil {
+		log.Printf("ERROR: pruning webhook deliveries: %s\n", err.Error())
+		return
+	}
+	log.Printf("INFO: pruned %d webhook deliveries\n", count)
+}
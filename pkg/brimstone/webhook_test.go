@@ -0,0 +1,80 @@
+package brimstone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	pam "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/privilegeaccessmanager"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimWebhookDelivery(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+
+	delivery, claimed, err := b.ClaimWebhookDelivery("sha256=aaaa", 1)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	_, claimed, err = b.ClaimWebhookDelivery("sha256=aaaa", 1)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	// same signature for another incident is a different delivery
+	_, claimed, err = b.ClaimWebhookDelivery("sha256=aaaa", 2)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// a released delivery can be claimed again by a GG retry
+	b.ReleaseWebhookDelivery(delivery)
+	_, claimed, err = b.ClaimWebhookDelivery("sha256=aaaa", 1)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// dry run only looks up deliveries
+	dryrun := b
+	dryrun.DryRun = true
+	_, claimed, err = dryrun.ClaimWebhookDelivery("sha256=bbbb", 1)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	_, claimed, err = b.ClaimWebhookDelivery("sha256=bbbb", 1)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	count, err := b.PruneWebhookDeliveries(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestGitGuardianEventPostDuplicate(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	b.PAMSessions = pam.NewSessionManager(pam.Config{})
+
+	e := echo.New()
+	post := func() *httptest.ResponseRecorder {
+		body := `{"action":"incident_assigned","incident":{"id":42}}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/notify/ggevent", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(gg.SIGNATURE_HEADER, "sha256=abcd")
+		rec := httptest.NewRecorder()
+		assert.NoError(t, b.GitGuardianEventPost(e.NewContext(req, rec), GitGuardianEventPostParams{}))
+		return rec
+	}
+
+	rec := post()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "recorded")
+	rec = post()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "duplicate")
+
+	var events []RemediationEvent
+	b.Db.Order("id").Find(&events)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "duplicate webhook delivery", events[0].Error)
+	assert.Equal(t, 42, events[0].IncidentID)
+}
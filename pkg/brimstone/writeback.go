@@ -0,0 +1,226 @@
+package brimstone
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// What brimstone writes back to the GG incident after remediating accounts of a safe
+const (
+	GG_WRITEBACK_NONE    = "none"    // leave the incident untouched
+	GG_WRITEBACK_NOTE    = "note"    // post a note with the rotation outcome
+	GG_WRITEBACK_RESOLVE = "resolve" // post the note and resolve the incident, secret revoked, once every rotation succeeded
+)
+
+// GG write-back of a safe; Mode and Assignee tell how the GG incidents of the safe accounts are
+// updated after remediation. Safes without a saved write-back are left untouched.
+type SafeWriteBack struct {
+	gorm.Model
+	Safename string `gorm:"uniqueIndex"`
+	Mode     string
+	Assignee string
+}
+
+// DefaultSafeWriteBack - write-back of a safe without a saved one, nothing is written back
+func DefaultSafeWriteBack(safename string) SafeWriteBack {
+	return SafeWriteBack{
+		Safename: safename,
+		Mode:     GG_WRITEBACK_NONE,
+	}
+}
+
+// Validate checks the write-back mode
+func (w SafeWriteBack) Validate() error {
+	switch w.Mode {
+	case GG_WRITEBACK_NONE, GG_WRITEBACK_NOTE, GG_WRITEBACK_RESOLVE:
+	default:
+		return fmt.Errorf("mode must be one of %s, %s or %s", GG_WRITEBACK_NONE, GG_WRITEBACK_NOTE, GG_WRITEBACK_RESOLVE)
+	}
+	return nil
+}
+
+// FetchSafeWriteBack - given safename return its GG write-back, or the default write-back
+func (b Brimstone) FetchSafeWriteBack(safename string) (SafeWriteBack, bool, error) {
+	db := b.Db
+
+	var writebacks []SafeWriteBack
+	result := db.Where(&SafeWriteBack{Safename: safename}).Limit(1).Find(&writebacks)
+	if result.Error != nil {
+		return SafeWriteBack{}, false, result.Error
+	}
+	if len(writebacks) == 0 {
+		return DefaultSafeWriteBack(safename), false, nil
+	}
+	return writebacks[0], true, nil
+}
+
+// remediationNote - GG incident note describing the remediation of the accounts of a safe
+func remediationNote(events []RemediationEvent) string {
+	var sb strings.Builder
+	sb.WriteString("Brimstone remediation:\n")
+	for i := 0; i < len(events); i++ {
+		outcome := "password rotated"
+		if events[i].RotationStatus == ROTATION_PENDING {
+			outcome = "password change requested, waiting for the CPM"
+		}
+		if events[i].Action == AUDIT_ACTION_ADD_ACCOUNT {
+			outcome = "account added to PAM"
+		}
+		if events[i].Error != "" {
+			outcome = fmt.Sprintf("%s failed, %s", events[i].Action, events[i].Error)
+		}
+		sb.WriteString(fmt.Sprintf("- safe: %s, account id: %s, %s\n", events[i].Safename, events[i].AccountID, outcome))
+	}
+	return sb.String()
+}
+
+// WriteBackIncident updates the GG incident with the remediation outcome, per the write-backs of the
+// safes involved: a note per safe, the incident assigned to the safe assignee, and the incident
+// resolved with the secret revoked when every safe asks for it and every rotation succeeded; a
+// pending rotation only gets the note, the incident is resolved once the CPM confirms it, see
+// checkRotation. Nothing is written without a GG client or in dry-run mode; failures are logged.
+func (b Brimstone) WriteBackIncident(ctx context.Context, incidentid int, events []RemediationEvent) {
+	client := b.GGClient
+	if client == nil || b.DryRun || incidentid == 0 || len(events) == 0 {
+		return
+	}
+
+	// group the events by safe, keeping their order
+	var safenames []string
+	bysafe := map[string][]RemediationEvent{}
+	for i := 0; i < len(events); i++ {
+		if _, ok := bysafe[events[i].Safename]; !ok {
+			safenames = append(safenames, events[i].Safename)
+		}
+		bysafe[events[i].Safename] = append(bysafe[events[i].Safename], events[i])
+	}
+
+	resolve := true
+	for i := 0; i < len(safenames); i++ {
+		writeback, _, err := b.FetchSafeWriteBack(safenames[i])
+		if err != nil {
+			log.Printf("ERROR: unable to fetch GG write-back of safe, %s: %s\n", safenames[i], err.Error())
+			resolve = false
+			continue
+		}
+		safeevents := bysafe[safenames[i]]
+		for j := 0; j < len(safeevents); j++ {
+			if safeevents[j].Action != AUDIT_ACTION_ROTATE || safeevents[j].Error != "" || safeevents[j].RotationStatus == ROTATION_PENDING {
+				resolve = false
+			}
+		}
+		if writeback.Mode != GG_WRITEBACK_RESOLVE {
+			resolve = false
+		}
+		if writeback.Mode == GG_WRITEBACK_NONE {
+			continue
+		}
+
+		noteres, err := client.CreateIncidentNoteWithResponse(ctx, incidentid, gg.CreateIncidentNoteJSONRequestBody{Comment: remediationNote(safeevents)})
+		if err != nil {
+			log.Printf("ERROR: unable to add note to GG incident, %d: %s\n", incidentid, err.Error())
+		} else if noteres.StatusCode() != http.StatusCreated {
+			log.Printf("ERROR: unable to add note to GG incident, %d: %s\n", incidentid, noteres.Status())
+		}
+
+		if writeback.Assignee != "" {
+			assignres, err := client.AssignIncidentWithResponse(ctx, incidentid, gg.AssignIncidentJSONRequestBody{Email: &writeback.Assignee})
+			if err != nil {
+				log.Printf("ERROR: unable to assign GG incident, %d, to %s: %s\n", incidentid, writeback.Assignee, err.Error())
+			} else if assignres.StatusCode() != http.StatusOK {
+				log.Printf("ERROR: unable to assign GG incident, %d, to %s: %s\n", incidentid, writeback.Assignee, assignres.Status())
+			}
+		}
+	}
+
+	if !resolve {
+		return
+	}
+	resolveres, err := client.ResolveIncidentWithResponse(ctx, incidentid, gg.ResolveIncidentJSONRequestBody{SecretRevoked: true})
+	if err != nil {
+		log.Printf("ERROR: unable to resolve GG incident, %d: %s\n", incidentid, err.Error())
+		return
+	}
+	if resolveres.StatusCode() != http.StatusOK {
+		log.Printf("ERROR: unable to resolve GG incident, %d: %s\n", incidentid, resolveres.Status())
+		return
+	}
+	log.Printf("INFO: resolved GG incident, %d, secret revoked\n", incidentid)
+}
+
+func safeWriteBackToAPI(w SafeWriteBack, saved bool) WriteBackPolicy {
+	isdefault := !saved
+	mode := WriteBackPolicyMode(w.Mode)
+	rsp := WriteBackPolicy{
+		Safename: &w.Safename,
+		Mode:     &mode,
+		Default:  &isdefault,
+	}
+	if w.Assignee != "" {
+		rsp.Assignee = &w.Assignee
+	}
+	return rsp
+}
+
+// SafeWriteBackGet - GET /v1/safes/{safename}/writeback
+func (b Brimstone) SafeWriteBackGet(ctx echo.Context, safename string) error {
+	writeback, saved, err := b.FetchSafeWriteBack(safename)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch write-back policy")
+	}
+	return ctx.JSON(200, safeWriteBackToAPI(writeback, saved))
+}
+
+// SafeWriteBackPut - PUT /v1/safes/{safename}/writeback
+func (b Brimstone) SafeWriteBackPut(ctx echo.Context, safename string) error {
+	db := b.Db
+
+	var req WriteBackPolicy
+	err := ctx.Bind(&req)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, "Invalid format for WriteBackPolicy")
+	}
+
+	writeback, _, err := b.FetchSafeWriteBack(safename)
+	if err != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to fetch write-back policy")
+	}
+	if req.Mode != nil {
+		writeback.Mode = string(*req.Mode)
+	}
+	if req.Assignee != nil {
+		writeback.Assignee = *req.Assignee
+	}
+	if err := writeback.Validate(); err != nil {
+		return sendBrimstoneError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result := db.Save(&writeback)
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to save write-back policy")
+	}
+
+	return ctx.JSON(200, safeWriteBackToAPI(writeback, true))
+}
+
+// SafeWriteBackDelete - DELETE /v1/safes/{safename}/writeback
+func (b Brimstone) SafeWriteBackDelete(ctx echo.Context, safename string) error {
+	db := b.Db
+
+	result := db.Unscoped().Where("safename = ?", safename).Delete(&SafeWriteBack{})
+	if result.Error != nil {
+		return sendBrimstoneError(ctx, http.StatusInternalServerError, "Unable to delete write-back policy")
+	}
+	if result.RowsAffected == 0 {
+		return sendBrimstoneError(ctx, http.StatusNotFound, fmt.Sprintf("no write-back policy for safe, %s", safename))
+	}
+
+	return ctx.JSON(200, "deleted")
+}
@@ -0,0 +1,157 @@
+package brimstone
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gg "github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/gitguardian"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type ggStub struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []string
+}
+
+func newGGStub(t *testing.T) (*ggStub, *gg.ClientWithResponses) {
+	stub := &ggStub{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, r.Method+" "+r.URL.Path)
+		stub.bodies = append(stub.bodies, string(body))
+		stub.mu.Unlock()
+		assert.Equal(t, "Token ggtoken", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/incidents/secrets/5/notes" {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := gg.NewClientWithToken(server.URL, "ggtoken")
+	assert.NoError(t, err)
+	return stub, client
+}
+
+func TestWriteBackIncidentNote(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	stub, client := newGGStub(t)
+	b.GGClient = client
+	b.Db.Create(&SafeWriteBack{Safename: "safe1", Mode: GG_WRITEBACK_NOTE})
+
+	events := []RemediationEvent{
+		{Action: AUDIT_ACTION_ROTATE, Safename: "safe1", AccountID: "1_1"},
+		{Action: AUDIT_ACTION_ROTATE, Safename: "safe1", AccountID: "1_2", Error: "pam unavailable"},
+	}
+	b.WriteBackIncident(context.Background(), 5, events)
+
+	assert.Equal(t, []string{"POST /v1/incidents/secrets/5/notes"}, stub.requests)
+	assert.Contains(t, stub.bodies[0], "account id: 1_1, password rotated")
+	assert.Contains(t, stub.bodies[0], "account id: 1_2, rotate failed, pam unavailable")
+}
+
+func TestWriteBackIncidentResolve(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	stub, client := newGGStub(t)
+	b.GGClient = client
+	b.Db.Create(&SafeWriteBack{Safename: "safe2", Mode: GG_WRITEBACK_RESOLVE, Assignee: "oncall@example.com"})
+
+	events := []RemediationEvent{{Action: AUDIT_ACTION_ROTATE, Safename: "safe2", AccountID: "2_1"}}
+	b.WriteBackIncident(context.Background(), 5, events)
+
+	assert.Equal(t, []string{
+		"POST /v1/incidents/secrets/5/notes",
+		"POST /v1/incidents/secrets/5/assign",
+		"POST /v1/incidents/secrets/5/resolve",
+	}, stub.requests)
+	assert.Contains(t, stub.bodies[1], "oncall@example.com")
+	assert.JSONEq(t, `{"secret_revoked":true}`, stub.bodies[2])
+
+	// a failed rotation leaves the incident open
+	stub.requests = nil
+	events[0].Error = "pam unavailable"
+	b.WriteBackIncident(context.Background(), 5, events)
+	assert.Len(t, stub.requests, 2)
+	assert.NotContains(t, stub.requests, "POST /v1/incidents/secrets/5/resolve")
+
+	// a pending rotation gets the note, the incident is resolved once the CPM confirms it
+	stub.requests, stub.bodies = nil, nil
+	events[0].Error = ""
+	events[0].RotationStatus = ROTATION_PENDING
+	b.WriteBackIncident(context.Background(), 5, events)
+	assert.Equal(t, []string{
+		"POST /v1/incidents/secrets/5/notes",
+		"POST /v1/incidents/secrets/5/assign",
+	}, stub.requests)
+	assert.Contains(t, stub.bodies[0], "waiting for the CPM")
+}
+
+func TestWriteBackIncidentDisabled(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	stub, client := newGGStub(t)
+	b.GGClient = client
+	b.Db.Create(&SafeWriteBack{Safename: "safe3", Mode: GG_WRITEBACK_NONE})
+	b.Db.Create(&SafeWriteBack{Safename: "safe1", Mode: GG_WRITEBACK_NOTE})
+
+	b.WriteBackIncident(context.Background(), 5, []RemediationEvent{{Action: AUDIT_ACTION_ROTATE, Safename: "safe3", AccountID: "3_1"}})
+
+	// off unless the safe opts in, even with a retention policy
+	b.Db.Create(&SafePolicy{Safename: "safe4", MaxHashCount: 1})
+	b.WriteBackIncident(context.Background(), 5, []RemediationEvent{{Action: AUDIT_ACTION_ROTATE, Safename: "safe4", AccountID: "4_1"}})
+
+	dryrun := b
+	dryrun.DryRun = true
+	dryrun.WriteBackIncident(context.Background(), 5, []RemediationEvent{{Action: AUDIT_ACTION_ROTATE, Safename: "safe1", AccountID: "1_1"}})
+
+	assert.Empty(t, stub.requests)
+}
+
+func TestSafeWriteBackHandlers(t *testing.T) {
+	b := newTestBrimstone(t, "http://127.0.0.1")
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, b.SafeWriteBackGet(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec), "safe1"))
+	var rsp WriteBackPolicy
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	assert.Equal(t, WriteBackPolicyMode(GG_WRITEBACK_NONE), *rsp.Mode)
+	assert.True(t, *rsp.Default)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, b.SafeWriteBackPut(e.NewContext(req, rec), "safe1"))
+		return rec
+	}
+	assert.Equal(t, http.StatusBadRequest, put(`{"mode": "close"}`).Code)
+	rec = put(`{"mode": "resolve", "assignee": "oncall@example.com"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	writeback, saved, err := b.FetchSafeWriteBack("safe1")
+	assert.NoError(t, err)
+	assert.True(t, saved)
+	assert.Equal(t, GG_WRITEBACK_RESOLVE, writeback.Mode)
+	assert.Equal(t, "oncall@example.com", writeback.Assignee)
+
+	// the retention policy of the safe is unchanged
+	_, saved, err = b.FetchSafePolicy("safe1")
+	assert.NoError(t, err)
+	assert.False(t, saved)
+
+	rec = httptest.NewRecorder()
+	assert.NoError(t, b.SafeWriteBackDelete(e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec), "safe1"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	_, saved, _ = b.FetchSafeWriteBack("safe1")
+	assert.False(t, saved)
+}
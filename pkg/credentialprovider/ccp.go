@@ -0,0 +1,195 @@
+package credentialprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Central Credential Provider REST endpoint
+const CCP_ACCOUNTS_PATH = "/AIMWebService/api/Accounts"
+
+// attribute of the password, returned by the CCP as Content
+const CCP_PASSWORD_ATTRIBUTE = "Password"
+
+// prefix of the account properties, the CCP returns them without it
+const CCP_PASSPROPS_PREFIX = "PassProps."
+
+// default timeout of a CCP request
+const CCP_DEFAULT_TIMEOUT = 30 * time.Second
+
+// CCPError - error returned by the CCP, ex: APPAP004E password object not found
+type CCPError struct {
+	StatusCode int    `json:"-"`
+	ErrorCode  string `json:"ErrorCode"`
+	ErrorMsg   string `json:"ErrorMsg"`
+}
+
+func (e CCPError) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("ccp request failed, status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("ccp request failed, status: %d, %s: %s", e.StatusCode, e.ErrorCode, e.ErrorMsg)
+}
+
+// CCPClient - fetches the properties from the Central Credential Provider REST endpoint, authenticated
+// with the client certificate; pure Go, safe for concurrent use
+type CCPClient struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewCCPClient - a CCPClient with the client certificate and CA of the config
+func NewCCPClient(config ClientConfig) (*CCPClient, error) {
+	if config.CCPURL == "" {
+		return nil, errors.New("ccp url is required")
+	}
+	if _, err := url.Parse(config.CCPURL); err != nil {
+		return nil, fmt.Errorf("invalid ccp url: %s", err.Error())
+	}
+
+	tlsconfig := &tls.Config{
+		InsecureSkipVerify: config.TLSSkipVerify, /* TLS_SKIP_VERIFY */
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, errors.New("ccp client certificate and key are both required")
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load ccp client certificate: %s", err.Error())
+		}
+		tlsconfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ccp ca: %s", err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in ccp ca, %s", config.CAFile)
+		}
+		tlsconfig.RootCAs = pool
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = CCP_DEFAULT_TIMEOUT
+	}
+	return &CCPClient{
+		URL: strings.TrimSuffix(config.CCPURL, "/"),
+		Client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsconfig},
+		},
+	}, nil
+}
+
+// ccpAttribute - name of the attribute in the CCP response
+func ccpAttribute(attr string) string {
+	if attr == CCP_PASSWORD_ATTRIBUTE {
+		return "Content"
+	}
+	return strings.TrimPrefix(attr, CCP_PASSPROPS_PREFIX)
+}
+
+// lookupAttribute - the attribute value, property names are matched ignoring case
+func lookupAttribute(values map[string]string, attr string) (string, bool) {
+	name := ccpAttribute(attr)
+	if val, ok := values[name]; ok {
+		return val, true
+	}
+	for k, v := range values {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// fetch - one request for every attribute of the account, cancelled with the ctx
+func (cl *CCPClient) fetch(ctx context.Context, props Properties) (map[string]string, error) {
+	query := url.Values{}
+	query.Set("AppID", props.AppID)
+	query.Set("Safe", props.SafeName)
+	query.Set("Object", props.ObjectName)
+	query.Set("FailRequestOnPasswordChange", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cl.URL+CCP_ACCOUNTS_PATH+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := cl.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		ccperr := CCPError{}
+		_ = json.Unmarshal(body, &ccperr)
+		ccperr.StatusCode = rsp.StatusCode
+		return nil, ccperr
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid ccp response: %s", err.Error())
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch val := v.(type) {
+		case nil:
+		case string:
+			values[k] = val
+		default:
+			values[k] = fmt.Sprint(val)
+		}
+	}
+	return values, nil
+}
+
+// FetchProperties - the requested attributes found on the account, missing attributes are
+// skipped as with the SDK; a failed request is returned as the error
+func (cl *CCPClient) FetchProperties(ctx context.Context, props Properties) (Properties, error) {
+	if props.Attributes == nil {
+		props.Attributes = make(map[string]string)
+	}
+	values, err := cl.fetch(ctx, props)
+	if err != nil {
+		return props, err
+	}
+	for _, attr := range props.RequestedAttributes {
+		if val, ok := lookupAttribute(values, attr); ok {
+			props.Attributes[attr] = val
+		}
+	}
+	return props, nil
+}
+
+func (cl *CCPClient) FetchProperty(ctx context.Context, props Properties, attr string) (string, error) {
+	values, err := cl.fetch(ctx, props)
+	if err != nil {
+		return "", err
+	}
+	val, ok := lookupAttribute(values, attr)
+	if !ok {
+		return "", fmt.Errorf("attribute not found, %s", attr)
+	}
+	return val, nil
+}
@@ -0,0 +1,166 @@
+package credentialprovider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCert - a self-signed client certificate and its key, as PEM files
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "brimstone"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyder, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certfile := filepath.Join(dir, "client.pem")
+	keyfile := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certfile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyfile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyder}), 0600))
+	return cert, certfile, keyfile
+}
+
+// newCCPStub - a CCP requiring the client certificate, returns the config of a client trusting it
+func newCCPStub(t *testing.T, handler http.HandlerFunc) ClientConfig {
+	dir := t.TempDir()
+	clientcert, certfile, keyfile := writeClientCert(t, dir)
+
+	server := httptest.NewUnstartedServer(handler)
+	clientcas := x509.NewCertPool()
+	clientcas.AddCert(clientcert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientcas}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	cafile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(cafile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	return ClientConfig{CCPURL: server.URL + "/", CertFile: certfile, KeyFile: keyfile, CAFile: cafile}
+}
+
+func ccpAccountHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, CCP_ACCOUNTS_PATH, r.URL.Path)
+		query := r.URL.Query()
+		if query.Get("AppID") != "Brimstone" || query.Get("Safe") != "Integration" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(CCPError{ErrorCode: "APPAP306E", ErrorMsg: "Authentication failed"})
+			return
+		}
+		assert.Equal(t, "true", query.Get("FailRequestOnPasswordChange"))
+		switch query.Get("Object") {
+		case "host":
+			json.NewEncoder(w).Encode(map[string]any{
+				"Content":     "hostpass",
+				"UserName":    "brimstone",
+				"Safe":        "Integration",
+				"Port":        "9191",
+				"APIKey":      "apikey",
+				"PAMPassword": "pampass",
+				"Retries":     3,
+				"Folder":      nil,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(CCPError{ErrorCode: "APPAP004E", ErrorMsg: "Password object matching query [Safe=Integration;Object=missing] was not found"})
+		}
+	}
+}
+
+func TestCCPFetchProperties(t *testing.T) {
+	config := newCCPStub(t, ccpAccountHandler(t))
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	attrs := []string{"Password", "PassProps.Port", "PassProps.APIKey", "PassProps.pampassword", "PassProps.Retries", "PassProps.Folder", "PassProps.DSN"}
+	props, err := client.FetchProperties(context.Background(), NewProperties("PASSWORD", "Integration", "Brimstone", "host", attrs))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Password":              "hostpass",
+		"PassProps.Port":        "9191",
+		"PassProps.APIKey":      "apikey",
+		"PassProps.pampassword": "pampass",
+		"PassProps.Retries":     "3",
+	}, props.Attributes)
+
+	val, err := client.FetchProperty(context.Background(), NewProperties("PASSWORD", "Integration", "Brimstone", "host", nil), "Password")
+	assert.NoError(t, err)
+	assert.Equal(t, "hostpass", val)
+
+	_, err = client.FetchProperty(context.Background(), NewProperties("PASSWORD", "Integration", "Brimstone", "host", nil), "PassProps.DSN")
+	assert.ErrorContains(t, err, "PassProps.DSN")
+}
+
+func TestCCPFetchErrors(t *testing.T) {
+	config := newCCPStub(t, ccpAccountHandler(t))
+	client, err := NewCCPClient(config)
+	require.NoError(t, err)
+
+	_, err = client.FetchProperties(context.Background(), NewProperties("PASSWORD", "Integration", "Brimstone", "missing", []string{"PassProps.DSN"}))
+	var ccperr CCPError
+	assert.ErrorAs(t, err, &ccperr)
+	assert.Equal(t, http.StatusNotFound, ccperr.StatusCode)
+	assert.Equal(t, "APPAP004E", ccperr.ErrorCode)
+
+	_, err = client.FetchProperty(context.Background(), NewProperties("PASSWORD", "Integration", "Other", "host", nil), "Password")
+	assert.ErrorContains(t, err, "APPAP306E")
+
+	// a cancelled request is not sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.FetchProperty(ctx, NewProperties("PASSWORD", "Integration", "Brimstone", "host", nil), "Password")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the CCP requires the client certificate
+	config.CertFile, config.KeyFile = "", ""
+	client, err = NewCCPClient(config)
+	require.NoError(t, err)
+	_, err = client.FetchProperty(context.Background(), NewProperties("PASSWORD", "Integration", "Brimstone", "host", nil), "Password")
+	assert.Error(t, err)
+}
+
+func TestNewCCPClientConfig(t *testing.T) {
+	_, err := NewCCPClient(ClientConfig{})
+	assert.ErrorContains(t, err, "url")
+
+	_, err = NewCCPClient(ClientConfig{CCPURL: "https://ccp.example.com", CertFile: "client.pem"})
+	assert.ErrorContains(t, err, "both required")
+
+	_, err = NewCCPClient(ClientConfig{CCPURL: "https://ccp.example.com", CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorContains(t, err, "ca")
+
+	client, err := NewCCPClient(ClientConfig{CCPURL: "https://ccp.example.com/"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ccp.example.com", client.URL)
+	assert.Equal(t, CCP_DEFAULT_TIMEOUT, client.Client.Timeout)
+}
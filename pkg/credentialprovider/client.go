@@ -1,3 +1,5 @@
+//go:build cgo && !ccp
+
 package credentialprovider
 
 /*
@@ -7,39 +9,27 @@ package credentialprovider
 import "C"
 
 import (
+	"context"
 	"fmt"
 )
 
+// Client - fetches the properties with the Credential Provider SDK, libcpasswordsdk; a Client
+// keeps the handles of its last request and is not safe for concurrent use
 type Client struct {
 	Request  C.ObjectHandle
 	Response C.ObjectHandle
 }
 
-type Properties struct {
-	RequestName         string
-	SafeName            string
-	AppID               string
-	ObjectName          string            // Aka AccountName
-	RequestedAttributes []string          // List of "PassProps.*" to fetch
-	Attributes          map[string]string // holds the values of the requested attributes
+func newSDKClient() (Fetcher, error) {
+	return &Client{}, nil
 }
 
-func NewProperties(reqname string, safe string, appid string, objname string, attrs []string) Properties {
-	props := Properties{
-		RequestName: reqname,
-		SafeName:    safe,
-		AppID:       appid,
-		ObjectName:  objname,
-	}
-	for i := 0; i < len(attrs); i++ {
-		props.RequestedAttributes = append(props.RequestedAttributes, attrs[i])
-	}
-	props.Attributes = make(map[string]string)
-	return props
-}
-func (cl *Client) FetchProperties(props Properties) (Properties, error) {
+func (cl *Client) FetchProperties(ctx context.Context, props Properties) (Properties, error) {
 	for _, attr := range props.RequestedAttributes {
-		val, err := cl.FetchProperty(props, attr)
+		if err := ctx.Err(); err != nil {
+			return props, err
+		}
+		val, err := cl.FetchProperty(ctx, props, attr)
 		if err == nil {
 			props.Attributes[attr] = val
 		}
@@ -47,7 +37,11 @@ func (cl *Client) FetchProperties(props Properties) (Properties, error) {
 	return props, nil
 }
 
-func (cl *Client) FetchProperty(props Properties, attr string) (string, error) {
+// FetchProperty - the SDK request blocks until it completes, the ctx is only checked before it
+func (cl *Client) FetchProperty(ctx context.Context, props Properties, attr string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	err := cl.CreateRequest(props.RequestName)
 	if err != nil {
 		return "", err
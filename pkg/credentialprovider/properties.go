@@ -0,0 +1,55 @@
+package credentialprovider
+
+import (
+	"context"
+	"time"
+)
+
+type Properties struct {
+	RequestName         string
+	SafeName            string
+	AppID               string
+	ObjectName          string            // Aka AccountName
+	RequestedAttributes []string          // List of "PassProps.*" to fetch
+	Attributes          map[string]string // holds the values of the requested attributes
+}
+
+func NewProperties(reqname string, safe string, appid string, objname string, attrs []string) Properties {
+	props := Properties{
+		RequestName: reqname,
+		SafeName:    safe,
+		AppID:       appid,
+		ObjectName:  objname,
+	}
+	for i := 0; i < len(attrs); i++ {
+		props.RequestedAttributes = append(props.RequestedAttributes, attrs[i])
+	}
+	props.Attributes = make(map[string]string)
+	return props
+}
+
+// Fetcher - fetches the requested attributes of an account, with the SDK Client or the CCPClient
+type Fetcher interface {
+	FetchProperties(ctx context.Context, props Properties) (Properties, error)
+	FetchProperty(ctx context.Context, props Properties, attr string) (string, error)
+}
+
+// ClientConfig - the Central Credential Provider REST endpoint is used when CCPURL is set,
+// otherwise the Credential Provider SDK installed on the host
+type ClientConfig struct {
+	CCPURL        string        // ex: https://ccp.example.com, without /AIMWebService
+	CertFile      string        // PEM client certificate, authenticates the application to the CCP
+	KeyFile       string        // PEM key of the client certificate
+	CAFile        string        // PEM CA bundle verifying the CCP certificate, the system roots when empty
+	Timeout       time.Duration // default is 30s
+	TLSSkipVerify bool
+}
+
+// NewClient - the Fetcher for the config; binaries built with -tags ccp, or without cgo, do not
+// include the SDK and require the CCP url
+func NewClient(config ClientConfig) (Fetcher, error) {
+	if config.CCPURL != "" {
+		return NewCCPClient(config)
+	}
+	return newSDKClient()
+}
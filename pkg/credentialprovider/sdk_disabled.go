@@ -0,0 +1,14 @@
+//go:build !cgo || ccp
+
+package credentialprovider
+
+import (
+	"errors"
+)
+
+// ErrSDKUnavailable - built with -tags ccp, or without cgo, the Credential Provider SDK is not included
+var ErrSDKUnavailable = errors.New("built without the credential provider sdk, the CCP url is required")
+
+func newSDKClient() (Fetcher, error) {
+	return nil, ErrSDKUnavailable
+}
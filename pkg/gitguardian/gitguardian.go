@@ -1,14 +1,24 @@
 package gitguardian
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Headers sent by the GG custom webhook
+const (
+	SIGNATURE_HEADER = "Gitguardian-Signature"
+	TIMESTAMP_HEADER = "timestamp"
+)
+
 type IncidentEvent struct {
 	Source     string    `json:"source,omitempty"`
 	Timestamp  time.Time `json:"timestamp,omitempty"`
@@ -42,3 +52,58 @@ func ValidateGGPayload(ggheader string, timestamp string, signature_token string
 	// If postbody sum matches the header hash, then this is a valid post
 	return hmac.Equal(sig, macsum)
 }
+
+// ValidateGGTimestamp - the webhook timestamp (unix seconds) is required and, when maxskew > 0,
+// must be within maxskew of now so a captured request cannot be replayed later on
+func ValidateGGTimestamp(timestamp string, maxskew time.Duration, now time.Time) error {
+	if timestamp == "" {
+		return fmt.Errorf("missing timestamp")
+	}
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp, %s", timestamp)
+	}
+	if maxskew <= 0 {
+		return nil
+	}
+	skew := now.Sub(time.Unix(secs, 0))
+	if skew > maxskew || skew < -maxskew {
+		return fmt.Errorf("timestamp outside of the allowed skew, %s", maxskew)
+	}
+	return nil
+}
+
+// NewClientWithToken - GG api client authenticated with the GG api token
+func NewClientWithToken(ggapiurl string, ggapitoken string, opts ...ClientOption) (*ClientWithResponses, error) {
+	authorization := fmt.Sprintf("Token %s", ggapitoken)
+	opts = append(opts, WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", authorization)
+		return nil
+	}))
+	return NewClientWithResponses(ggapiurl, opts...)
+}
+
+// NextCursor - cursor of the next page, from the Link header of a paginated GG api response;
+// empty on the last page
+func NextCursor(res *http.Response) string {
+	if res == nil {
+		return ""
+	}
+	links := strings.Split(res.Header.Get("Link"), ",")
+	for i := 0; i < len(links); i++ {
+		if !strings.Contains(links[i], `rel="next"`) {
+			continue
+		}
+		start := strings.Index(links[i], "<")
+		end := strings.Index(links[i], ">")
+		if start < 0 || end < start {
+			return ""
+		}
+		u, err := url.Parse(links[i][start+1 : end])
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("cursor")
+	}
+	return ""
+}
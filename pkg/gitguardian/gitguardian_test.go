@@ -1,7 +1,10 @@
 package gitguardian
 
 import (
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -24,3 +27,32 @@ func TestValidateGGPayloadFalse(t *testing.T) {
 	a := ValidateGGPayload(signature, timestamp, signature_token, payload)
 	assert.False(t, a)
 }
+
+func TestValidateGGTimestamp(t *testing.T) {
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	old := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)
+
+	assert.NoError(t, ValidateGGTimestamp(ts, 5*time.Minute, now))
+	assert.Error(t, ValidateGGTimestamp("", 5*time.Minute, now))
+	assert.Error(t, ValidateGGTimestamp("yesterday", 5*time.Minute, now))
+	assert.Error(t, ValidateGGTimestamp(old, 5*time.Minute, now))
+	assert.Error(t, ValidateGGTimestamp(future, 5*time.Minute, now))
+
+	// no max skew only requires the timestamp
+	assert.NoError(t, ValidateGGTimestamp(old, 0, now))
+	assert.Error(t, ValidateGGTimestamp("", 0, now))
+}
+
+func TestNextCursor(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	assert.Equal(t, "", NextCursor(res))
+
+	res.Header.Set("Link", `<https://api.gitguardian.com/v1/incidents/secrets?cursor=cD0yMDI0&per_page=100>; rel="next"`)
+	assert.Equal(t, "cD0yMDI0", NextCursor(res))
+
+	res.Header.Set("Link", `<https://api.gitguardian.com/v1/incidents/secrets?cursor=abc>; rel="prev"`)
+	assert.Equal(t, "", NextCursor(res))
+	assert.Equal(t, "", NextCursor(nil))
+}
@@ -30,7 +30,8 @@ type AuthenticatePostResponse struct {
 }
 
 // TODO: (low priority) move GG authentication into handler functions since the token retrieved is a short-lived session token
-func NewClientAuthenticateWithGitGuardian(ctx context.Context, hmslurl *string, audiencetype *string, ggapiurl *string, ggapitoken *string) (*ClientWithResponses, error) {
+// opts are passed on to the HMSL client, ex: WithHTTPClient for an instrumented http client
+func NewClientAuthenticateWithGitGuardian(ctx context.Context, hmslurl *string, audiencetype *string, ggapiurl *string, ggapitoken *string, opts ...ClientOption) (*ClientWithResponses, error) {
 
 	jwtreq := gg.PublicJwtCreateJSONRequestBody{
 		Audience:     *hmslurl,
@@ -53,7 +54,8 @@ func NewClientAuthenticateWithGitGuardian(ctx context.Context, hmslurl *string,
 		panic(bearerTokenProviderErr)
 	}
 
-	return NewClientWithResponses(*hmslurl, WithRequestEditorFn(bearerTokenProvider.Intercept))
+	opts = append(opts, WithRequestEditorFn(bearerTokenProvider.Intercept))
+	return NewClientWithResponses(*hmslurl, opts...)
 }
 
 func AuthenticateWithGitGuardian(ctx context.Context, ggapiurl string, ggapitoken string, body gg.PublicJwtCreateJSONRequestBody) (*http.Response, error) {
@@ -46,6 +46,9 @@ def decrypt_payload(payload_str: str, key: str) -> str:
 	return cleartext.decode()
 */
 func DecryptPayload(p []byte, k string) (string, error) {
+	if len(p) < 12 {
+		return "", fmt.Errorf("payload too short")
+	}
 	key, _ := hex.DecodeString(k)
 	// ciphertext, err := base64.StdEncoding.DecodeString(p[12:])
 	ciphertext := p[12:]
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const NAMESPACE = "brimstone"
+
+// label value for requests that failed before a response was received
+const CODE_ERROR = "error"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_requests_total",
+		Help:      "Requests handled by brimstone, by route and status code",
+	}, []string{"method", "path", "code"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the requests handled by brimstone, by route",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path"})
+
+	WebhookEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "gg_webhook_events_total",
+		Help:      "GitGuardian webhook events received, by incident action",
+	}, []string{"action"})
+
+	HMSLRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "hmsl_requests_total",
+		Help:      "Requests sent to HMSL, by endpoint and status code",
+	}, []string{"method", "endpoint", "code"})
+
+	HMSLRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "hmsl_request_duration_seconds",
+		Help:      "Latency of the requests sent to HMSL, by endpoint",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+
+	PAMRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "pam_requests_total",
+		Help:      "Requests sent to the PAM api, by endpoint and status code",
+	}, []string{"method", "endpoint", "code"})
+
+	PAMRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "pam_request_duration_seconds",
+		Help:      "Latency of the requests sent to the PAM api, by endpoint",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+
+	LeaksFound = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "leaks_found_total",
+		Help:      "Leaked hashes reported by HMSL, by safe and scan mode",
+	}, []string{"safename", "mode"})
+
+	RotationsAttempted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "rotations_attempted_total",
+		Help:      "Password changes requested from PAM, by safe",
+	}, []string{"safename"})
+
+	Rotations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "rotations_total",
+		Help:      "Outcome of the password changes requested from PAM, by safe and result",
+	}, []string{"safename", "result"})
+
+	ScanDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "scan_duration_seconds",
+		Help:      "Duration of leak scans, by scan mode",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"mode"})
+
+	storedHashesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(NAMESPACE, "", "stored_hashes"),
+		"Hash versions stored by brimstone, by safe",
+		[]string{"safename"}, nil,
+	)
+)
+
+// ids in PAM api paths, ex: /PasswordVault/API/Accounts/12_3/Change/
+var pathIdRegexp = regexp.MustCompile(`/[0-9]+(_[0-9]+)?(/|$)`)
+
+// Handler - prometheus scrape endpoint
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RecordRotation counts a password change requested from PAM and its outcome
+func RecordRotation(safename string, err error) {
+	RotationsAttempted.WithLabelValues(safename).Inc()
+	result := "succeeded"
+	if err != nil {
+		result = "failed"
+	}
+	Rotations.WithLabelValues(safename, result).Inc()
+}
+
+// EchoMiddleware counts and times the requests handled by brimstone, labelled by route
+// rather than by path so ids in the path do not create new series
+func EchoMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			method := c.Request().Method
+			path := c.Path()
+			HTTPRequests.WithLabelValues(method, path, strconv.Itoa(c.Response().Status)).Inc()
+			HTTPRequestDuration.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+type instrumentedTransport struct {
+	next     http.RoundTripper
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	endpoint := pathIdRegexp.ReplaceAllString(req.URL.Path, "/{id}$2")
+	code := CODE_ERROR
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	t.requests.WithLabelValues(req.Method, endpoint, code).Inc()
+	t.duration.WithLabelValues(req.Method, endpoint).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+// InstrumentTransport - returns a wrapper of http transports that counts and times the requests
+// with the given collectors; both are labelled by method and endpoint, the counter also by code
+func InstrumentTransport(requests *prometheus.CounterVec, duration *prometheus.HistogramVec) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return instrumentedTransport{
+			next:     next,
+			requests: requests,
+			duration: duration,
+		}
+	}
+}
+
+// InstrumentedClient - http client whose requests are counted and timed with the given collectors
+func InstrumentedClient(requests *prometheus.CounterVec, duration *prometheus.HistogramVec) *http.Client {
+	return &http.Client{
+		Transport: InstrumentTransport(requests, duration)(http.DefaultTransport),
+	}
+}
+
+type storedHashesCollector struct {
+	count func() (map[string]int, error)
+}
+
+func (c storedHashesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- storedHashesDesc
+}
+
+func (c storedHashesCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.count()
+	if err != nil {
+		log.Printf("ERROR: unable to count stored hashes: %s\n", err.Error())
+		return
+	}
+	for safename, count := range counts {
+		ch <- prometheus.MustNewConstMetric(storedHashesDesc, prometheus.GaugeValue, float64(count), safename)
+	}
+}
+
+// RegisterStoredHashes registers the per-safe stored hash gauge; count is called on every
+// scrape so the gauge always reflects the db
+func RegisterStoredHashes(count func() (map[string]int, error)) error {
+	return prometheus.Register(storedHashesCollector{count: count})
+}
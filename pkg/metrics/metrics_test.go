@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func counterValue(t *testing.T, c *prometheus.CounterVec, labels ...string) float64 {
+	m := &dto.Metric{}
+	assert.NoError(t, c.WithLabelValues(labels...).Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_requests_total"}, []string{"method", "endpoint", "code"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_request_duration_seconds"}, []string{"method", "endpoint"})
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer stub.Close()
+
+	client := InstrumentedClient(requests, duration)
+	res, err := client.Post(stub.URL+"/PasswordVault/API/Accounts/12_34/Change/", "application/json", nil)
+	assert.NoError(t, err)
+	res.Body.Close()
+	res, err = client.Get(stub.URL + "/PasswordVault/API/Accounts/56_78/Change/?search=x")
+	assert.NoError(t, err)
+	res.Body.Close()
+
+	// account ids are not part of the endpoint label
+	assert.Equal(t, float64(1), counterValue(t, requests, "POST", "/PasswordVault/API/Accounts/{id}/Change/", "404"))
+	assert.Equal(t, float64(1), counterValue(t, requests, "GET", "/PasswordVault/API/Accounts/{id}/Change/", "404"))
+
+	// requests without a response are counted as errors
+	stub.Close()
+	_, err = client.Get(stub.URL + "/v1/hashes")
+	assert.Error(t, err)
+	assert.Equal(t, float64(1), counterValue(t, requests, "GET", "/v1/hashes", CODE_ERROR))
+}
+
+func TestRecordRotation(t *testing.T) {
+	before := counterValue(t, Rotations, "safe1", "failed")
+	RecordRotation("safe1", assert.AnError)
+	RecordRotation("safe1", nil)
+	assert.Equal(t, before+1, counterValue(t, Rotations, "safe1", "failed"))
+	assert.Equal(t, float64(2), counterValue(t, RotationsAttempted, "safe1"))
+}
@@ -0,0 +1,146 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// max number of accounts per page accepted by the accounts api
+const ACCOUNTS_PAGE_LIMIT = 1000
+
+// FetchAccountsOptions - server-side filters of the accounts list
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/SDK/GetAccounts.htm
+type FetchAccountsOptions struct {
+	SafeName   string // only the accounts of the safe, filter=safeName eq ...
+	Search     string // keywords searched in the account properties
+	SearchType string // contains (default) or startswith
+	Limit      int    // accounts per page, default and max ACCOUNTS_PAGE_LIMIT
+}
+
+func (o FetchAccountsOptions) query() url.Values {
+	q := url.Values{}
+	limit := ACCOUNTS_PAGE_LIMIT
+	if o.Limit > 0 && o.Limit < ACCOUNTS_PAGE_LIMIT {
+		limit = o.Limit
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	if o.SafeName != "" {
+		q.Set("filter", fmt.Sprintf("safeName eq %s", o.SafeName))
+	}
+	if o.Search != "" {
+		q.Set("search", o.Search)
+	}
+	if o.SearchType != "" {
+		q.Set("searchType", o.SearchType)
+	}
+	return q
+}
+
+// AccountIterator pages through the accounts list following nextLink, one page in memory at a time
+//
+//	it := client.Accounts(ctx, pam.FetchAccountsOptions{SafeName: "safe1"})
+//	for it.Next() {
+//		account := it.Account()
+//	}
+//	if it.Err() != nil { ... }
+type AccountIterator struct {
+	ctx     context.Context
+	client  *Client
+	nexturl string
+	page    []Account
+	pos     int
+	err     error
+}
+
+// Accounts - iterator over the accounts matching the options
+func (c *Client) Accounts(ctx context.Context, opts FetchAccountsOptions) *AccountIterator {
+	return &AccountIterator{
+		ctx:     ctx,
+		client:  c,
+		nexturl: fmt.Sprintf("%s/PasswordVault/API/Accounts?%s", c.Config.PCloudURL, opts.query().Encode()),
+		pos:     -1,
+	}
+}
+
+// Next advances to the next account, fetching the next page when needed; returns false when
+// there are no more accounts or a page could not be fetched
+func (it *AccountIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.page) {
+		if it.nexturl == "" {
+			return false
+		}
+		page, _, err := it.client.fetchAccountsPage(it.ctx, it.nexturl)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.nexturl, err = it.client.resolveNextLink(page.NextLink)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page.Value
+		it.pos = 0
+	}
+	return true
+}
+
+// Account - the current account
+func (it *AccountIterator) Account() Account {
+	return it.page[it.pos]
+}
+
+// Err - the error that stopped the iteration, if any
+func (it *AccountIterator) Err() error {
+	return it.err
+}
+
+// FetchAccountsWithOptions - every account matching the options, all pages
+func (c *Client) FetchAccountsWithOptions(ctx context.Context, opts FetchAccountsOptions) ([]Account, error) {
+	var accounts []Account
+	it := c.Accounts(ctx, opts)
+	for it.Next() {
+		accounts = append(accounts, it.Account())
+	}
+	return accounts, it.Err()
+}
+
+// resolveNextLink - nextLink is relative to /PasswordVault/, ex: api/Accounts?offset=50&limit=50
+func (c *Client) resolveNextLink(nextlink string) (string, error) {
+	if nextlink == "" {
+		return "", nil
+	}
+	base, err := url.Parse(fmt.Sprintf("%s/PasswordVault/", strings.TrimSuffix(c.Config.PCloudURL, "/")))
+	if err != nil {
+		return "", err
+	}
+	next, err := url.Parse(nextlink)
+	if err != nil {
+		return "", fmt.Errorf("invalid nextLink, %s: %w", nextlink, err)
+	}
+	return base.ResolveReference(next).String(), nil
+}
+
+// fetchAccountsPage - one page of accounts and the response status code
+func (c *Client) fetchAccountsPage(ctx context.Context, apiurl string) (FetchAccountsResponse, int, error) {
+	var page FetchAccountsResponse
+
+	body, code, err := c.do(ctx, http.MethodGet, apiurl, "", nil)
+	if err != nil {
+		return page, code, err
+	}
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return page, http.StatusBadGateway, fmt.Errorf("failed to parse accounts response: %w", err)
+	}
+	return page, code, nil
+}
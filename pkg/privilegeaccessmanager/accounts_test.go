@@ -0,0 +1,127 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newAccountsStub serves the accounts api, total accounts in pages of limit accounts; the queries
+// received are recorded
+func newAccountsStub(t *testing.T, total int, queries *[]string) *httptest.Server {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/PasswordVault/API/Accounts" && r.URL.Path != "/PasswordVault/api/Accounts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		*queries = append(*queries, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		res := FetchAccountsResponse{Count: total}
+		for i := offset; i < total && i < offset+limit; i++ {
+			res.Value = append(res.Value, Account{ID: fmt.Sprintf("12_%d", i), Name: fmt.Sprintf("account%d", i), SafeName: "safe1"})
+		}
+		if offset+limit < total {
+			// relative to /PasswordVault/, as sent by the vault
+			res.NextLink = fmt.Sprintf("api/Accounts?offset=%d&limit=%d", offset+limit, limit)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func newTestClient(pcloudurl string) Client {
+	config := NewConfig("", pcloudurl, "safe1", "DummyPlatform", "", "", false)
+	client := NewClient(pcloudurl, config)
+	client.Session = NewSession("tok", "Bearer", time.Now().Add(time.Hour))
+	return client
+}
+
+func TestFetchAccountsFollowsNextLink(t *testing.T) {
+	var queries []string
+	stub := newAccountsStub(t, 5, &queries)
+	client := newTestClient(stub.URL)
+
+	accounts, err := client.FetchAccountsWithOptions(context.Background(), FetchAccountsOptions{Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 5)
+	assert.Equal(t, "12_0", accounts[0].ID)
+	assert.Equal(t, "12_4", accounts[4].ID)
+	assert.Len(t, queries, 3)
+
+	// without options the max page size is requested
+	queries = nil
+	accounts, err = client.FetchAccounts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 5)
+	assert.Equal(t, []string{"limit=1000"}, queries)
+}
+
+func TestFetchAccountsQuery(t *testing.T) {
+	var queries []string
+	stub := newAccountsStub(t, 1, &queries)
+	client := newTestClient(stub.URL)
+
+	_, err := client.FetchAccountsWithOptions(context.Background(), FetchAccountsOptions{SafeName: "safe 1", Search: "admin", SearchType: "startswith"})
+	assert.NoError(t, err)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, "filter=safeName+eq+safe+1&limit=1000&search=admin&searchType=startswith", queries[0])
+}
+
+func TestAccountIterator(t *testing.T) {
+	var queries []string
+	stub := newAccountsStub(t, 3, &queries)
+	client := newTestClient(stub.URL)
+
+	var ids []string
+	it := client.Accounts(context.Background(), FetchAccountsOptions{Limit: 2})
+	for it.Next() {
+		ids = append(ids, it.Account().ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"12_0", "12_1", "12_2"}, ids)
+	assert.False(t, it.Next())
+
+	// an empty vault
+	stub = newAccountsStub(t, 0, &queries)
+	empty := newTestClient(stub.URL)
+	it = empty.Accounts(context.Background(), FetchAccountsOptions{})
+	assert.False(t, it.Next())
+	assert.NoError(t, it.Err())
+
+	// a failed page stops the iteration
+	client.Session.Token = "expired"
+	it = client.Accounts(context.Background(), FetchAccountsOptions{})
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), ErrAuth)
+}
+
+func TestFetchAccountIdFromAccountName(t *testing.T) {
+	var queries []string
+	stub := newAccountsStub(t, 5, &queries)
+	client := newTestClient(stub.URL)
+
+	// found on the last page
+	id, code, err := client.FetchAccountIdFromAccountName(context.Background(), "safe1", "account4")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "12_4", id)
+
+	_, code, err = client.FetchAccountIdFromAccountName(context.Background(), "safe1", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,96 @@
+package privilegeaccessmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Kinds of PAM errors, test them with errors.Is
+var (
+	ErrAuth        = errors.New("pam authentication failed") // 401, 403, or no session token issued
+	ErrNotFound    = errors.New("pam resource not found")    // 404
+	ErrRateLimited = errors.New("pam rate limit exceeded")   // 429
+	ErrServer      = errors.New("pam server error")          // 5xx
+	ErrRequest     = errors.New("pam request rejected")      // any other status
+)
+
+// APIError - error response of the PAM api, with the http status and the PAM error code
+type APIError struct {
+	StatusCode int
+	ErrorCode  string // ex: PASWS013E, or the identity error, ex: invalid_client
+	Message    string
+	Kind       error
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s (code=%d)", e.Kind.Error(), e.StatusCode))
+	if e.ErrorCode != "" {
+		sb.WriteString(fmt.Sprintf(" %s", e.ErrorCode))
+	}
+	if e.Message != "" {
+		sb.WriteString(fmt.Sprintf(": %s", e.Message))
+	}
+	return sb.String()
+}
+
+// Unwrap - the kind of the error
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// errorResponse - error body of the vault api, or of the identity tenant
+type errorResponse struct {
+	ErrorCode        string `json:"ErrorCode,omitempty"`
+	ErrorMessage     string `json:"ErrorMessage,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// errorKind - kind of error of the http status
+func errorKind(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrServer
+	}
+	return ErrRequest
+}
+
+// newAPIError - error of the PAM response, the message is the body when it is not a PAM error body
+func newAPIError(status int, body []byte) *APIError {
+	apierr := &APIError{
+		StatusCode: status,
+		Kind:       errorKind(status),
+	}
+	var errres errorResponse
+	if json.Unmarshal(body, &errres) == nil {
+		apierr.ErrorCode = errres.ErrorCode
+		apierr.Message = errres.ErrorMessage
+		if errres.Error != "" {
+			apierr.ErrorCode = errres.Error
+			apierr.Message = errres.ErrorDescription
+		}
+	}
+	if apierr.ErrorCode == "" && apierr.Message == "" {
+		apierr.Message = strings.TrimSpace(string(body))
+	}
+	return apierr
+}
+
+// StatusCode - http status of the PAM response that failed, 502 when PAM did not respond
+func StatusCode(err error) int {
+	var apierr *APIError
+	if errors.As(err, &apierr) {
+		return apierr.StatusCode
+	}
+	return http.StatusBadGateway
+}
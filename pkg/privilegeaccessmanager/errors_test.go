@@ -0,0 +1,43 @@
+package privilegeaccessmanager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		status  int
+		body    string
+		kind    error
+		code    string
+		message string
+	}{
+		{http.StatusUnauthorized, `{"ErrorCode":"PASWS013E","ErrorMessage":"Session expired"}`, ErrAuth, "PASWS013E", "Session expired"},
+		{http.StatusForbidden, ``, ErrAuth, "", ""},
+		{http.StatusNotFound, `{"ErrorCode":"PASWS165E","ErrorMessage":"Account not found"}`, ErrNotFound, "PASWS165E", "Account not found"},
+		{http.StatusTooManyRequests, `too many requests`, ErrRateLimited, "", "too many requests"},
+		{http.StatusServiceUnavailable, `<html>unavailable</html>`, ErrServer, "", "<html>unavailable</html>"},
+		{http.StatusBadRequest, `{"error":"invalid_request","error_description":"missing grant_type"}`, ErrRequest, "invalid_request", "missing grant_type"},
+	}
+	for i := 0; i < len(tests); i++ {
+		apierr := newAPIError(tests[i].status, []byte(tests[i].body))
+		assert.ErrorIs(t, apierr, tests[i].kind)
+		assert.Equal(t, tests[i].status, apierr.StatusCode)
+		assert.Equal(t, tests[i].code, apierr.ErrorCode)
+		assert.Equal(t, tests[i].message, apierr.Message)
+	}
+
+	assert.Equal(t, "pam resource not found (code=404) PASWS165E: Account not found", newAPIError(404, []byte(`{"ErrorCode":"PASWS165E","ErrorMessage":"Account not found"}`)).Error())
+}
+
+func TestStatusCode(t *testing.T) {
+	err := fmt.Errorf("rotating: %w", newAPIError(http.StatusTooManyRequests, nil))
+	assert.Equal(t, http.StatusTooManyRequests, StatusCode(err))
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, http.StatusBadGateway, StatusCode(errors.New("connection refused")))
+}
@@ -0,0 +1,126 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PatchOperation - one change of the account properties, ex: replace /address
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/WebServices/UpdateAccount%20v10.htm
+type PatchOperation struct {
+	Op    string `json:"op"`   // add, remove or replace
+	Path  string `json:"path"` // ex: /address, /platformAccountProperties/IncidentDetails
+	Value any    `json:"value,omitempty"`
+}
+
+type PostSetNextPasswordRequest struct {
+	ChangeImmediately bool   `json:"ChangeImmediately"`
+	NewCredentials    string `json:"NewCredentials"`
+}
+
+// AccountActivity - activity of the account audit log
+type AccountActivity struct {
+	Alert    bool   `json:"Alert"`
+	ClientID string `json:"ClientID,omitempty"`
+	Date     int64  `json:"Date,omitempty"`
+	MoreInfo string `json:"MoreInfo,omitempty"`
+	Reason   string `json:"Reason,omitempty"`
+	User     string `json:"User,omitempty"`
+	Action   string `json:"Action,omitempty"`
+	ActionID int    `json:"ActionID,omitempty"`
+}
+
+type GetAccountActivitiesResponse struct {
+	Activities []AccountActivity `json:"Activities"`
+}
+
+func (c *Client) accountURL(accountid string) string {
+	return fmt.Sprintf("%s/PasswordVault/API/Accounts/%s", c.Config.PCloudURL, url.PathEscape(accountid))
+}
+
+// GetAccount - GET /PasswordVault/API/Accounts/{id}
+func (c *Client) GetAccount(ctx context.Context, accountid string) (Account, error) {
+	var account Account
+	body, _, err := c.do(ctx, http.MethodGet, c.accountURL(accountid), "", nil)
+	if err != nil {
+		return account, err
+	}
+	err = json.Unmarshal(body, &account)
+	if err != nil {
+		return account, fmt.Errorf("failed to parse account response: %w", err)
+	}
+	return account, nil
+}
+
+// DeleteAccount - DELETE /PasswordVault/API/Accounts/{id}
+func (c *Client) DeleteAccount(ctx context.Context, accountid string) error {
+	_, _, err := c.do(ctx, http.MethodDelete, c.accountURL(accountid), "", nil)
+	return err
+}
+
+// VerifyCredentials - marks the account for verification by the CPM
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/WebServices/Verify-credentials-v9-10.htm
+func (c *Client) VerifyCredentials(ctx context.Context, accountid string) error {
+	_, _, err := c.do(ctx, http.MethodPost, c.accountURL(accountid)+"/Verify", "", nil)
+	return err
+}
+
+// ReconcileCredentials - marks the account for reconciliation by the CPM, with the reconcile account
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/SDK/Reconcile-account.htm
+func (c *Client) ReconcileCredentials(ctx context.Context, accountid string) error {
+	_, _, err := c.do(ctx, http.MethodPost, c.accountURL(accountid)+"/Reconcile", "", nil)
+	return err
+}
+
+// SetNextPassword - the password the CPM sets at the next change, now when changeimmediately
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/SDK/SetNextPassword.htm
+func (c *Client) SetNextPassword(ctx context.Context, accountid string, password string, changeimmediately bool) error {
+	postbody := PostSetNextPasswordRequest{
+		ChangeImmediately: changeimmediately,
+		NewCredentials:    password,
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPost, c.accountURL(accountid)+"/SetNextPassword", postbody)
+	return err
+}
+
+// UpdateAccount - PATCH /PasswordVault/API/Accounts/{id}, returns the updated account
+func (c *Client) UpdateAccount(ctx context.Context, accountid string, operations []PatchOperation) (Account, error) {
+	var account Account
+	body, _, err := c.doJSON(ctx, http.MethodPatch, c.accountURL(accountid), operations)
+	if err != nil {
+		return account, err
+	}
+	err = json.Unmarshal(body, &account)
+	if err != nil {
+		return account, fmt.Errorf("failed to parse account response: %w", err)
+	}
+	return account, nil
+}
+
+// SetAutomaticManagement - enable the CPM management of the account, or disable it with the reason
+func (c *Client) SetAutomaticManagement(ctx context.Context, accountid string, enabled bool, reason string) (Account, error) {
+	operations := []PatchOperation{
+		{Op: "replace", Path: "/secretManagement/automaticManagementEnabled", Value: enabled},
+	}
+	if !enabled && reason != "" {
+		operations = append(operations, PatchOperation{Op: "replace", Path: "/secretManagement/manualManagementReason", Value: reason})
+	}
+	return c.UpdateAccount(ctx, accountid, operations)
+}
+
+// FetchAccountActivities - GET /PasswordVault/API/Accounts/{id}/Activities
+func (c *Client) FetchAccountActivities(ctx context.Context, accountid string) ([]AccountActivity, error) {
+	var resp GetAccountActivitiesResponse
+	body, _, err := c.do(ctx, http.MethodGet, c.accountURL(accountid)+"/Activities", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse account activities response: %w", err)
+	}
+	return resp.Activities, nil
+}
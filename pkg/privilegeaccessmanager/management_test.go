@@ -0,0 +1,102 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// request received by the stub
+type received struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// newRecordingStub - PAM stub recording the requests, responding with the status and body
+func newRecordingStub(t *testing.T, status int, resbody string, requests *[]received) Client {
+	return newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*requests = append(*requests, received{Method: r.Method, Path: r.URL.Path, Body: string(body)})
+		w.WriteHeader(status)
+		w.Write([]byte(resbody))
+	})
+}
+
+func TestGetAccount(t *testing.T) {
+	var requests []received
+	client := newRecordingStub(t, http.StatusOK, `{"id":"12_1","name":"acct","safeName":"safe1","secretManagement":{"automaticManagementEnabled":true,"status":"success"}}`, &requests)
+
+	account, err := client.GetAccount(context.Background(), "12_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "acct", account.Name)
+	assert.True(t, account.SecretManagement.AutomaticManagementEnabled)
+	assert.Equal(t, []received{{Method: http.MethodGet, Path: "/PasswordVault/API/Accounts/12_1"}}, requests)
+
+	client = newRecordingStub(t, http.StatusNotFound, `{"ErrorCode":"PASWS165E","ErrorMessage":"Account not found"}`, &requests)
+	_, err = client.GetAccount(context.Background(), "12_2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAccountActions(t *testing.T) {
+	var requests []received
+	client := newRecordingStub(t, http.StatusOK, ``, &requests)
+
+	ctx := context.Background()
+	assert.NoError(t, client.DeleteAccount(ctx, "12_1"))
+	assert.NoError(t, client.VerifyCredentials(ctx, "12_1"))
+	assert.NoError(t, client.ReconcileCredentials(ctx, "12_1"))
+	assert.NoError(t, client.SetNextPassword(ctx, "12_1", "n3xt", true))
+
+	assert.Equal(t, []received{
+		{Method: http.MethodDelete, Path: "/PasswordVault/API/Accounts/12_1"},
+		{Method: http.MethodPost, Path: "/PasswordVault/API/Accounts/12_1/Verify"},
+		{Method: http.MethodPost, Path: "/PasswordVault/API/Accounts/12_1/Reconcile"},
+		{Method: http.MethodPost, Path: "/PasswordVault/API/Accounts/12_1/SetNextPassword", Body: `{"ChangeImmediately":true,"NewCredentials":"n3xt"}`},
+	}, requests)
+
+	requests = nil
+	client = newRecordingStub(t, http.StatusForbidden, `{"ErrorCode":"PASWS041E","ErrorMessage":"missing permission"}`, &requests)
+	assert.ErrorIs(t, client.DeleteAccount(ctx, "12_1"), ErrAuth)
+}
+
+func TestUpdateAccount(t *testing.T) {
+	var requests []received
+	client := newRecordingStub(t, http.StatusOK, `{"id":"12_1","address":"db.example.com"}`, &requests)
+
+	ctx := context.Background()
+	account, err := client.UpdateAccount(ctx, "12_1", []PatchOperation{{Op: "replace", Path: "/address", Value: "db.example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "db.example.com", account.Address)
+	assert.Equal(t, http.MethodPatch, requests[0].Method)
+	assert.JSONEq(t, `[{"op":"replace","path":"/address","value":"db.example.com"}]`, requests[0].Body)
+
+	requests = nil
+	_, err = client.SetAutomaticManagement(ctx, "12_1", false, "leaked, rotated by hand")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"replace","path":"/secretManagement/automaticManagementEnabled","value":false},{"op":"replace","path":"/secretManagement/manualManagementReason","value":"leaked, rotated by hand"}]`, requests[0].Body)
+
+	requests = nil
+	_, err = client.SetAutomaticManagement(ctx, "12_1", true, "")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"replace","path":"/secretManagement/automaticManagementEnabled","value":true}]`, requests[0].Body)
+}
+
+func TestFetchAccountActivities(t *testing.T) {
+	var requests []received
+	activities := GetAccountActivitiesResponse{Activities: []AccountActivity{
+		{Action: "CPM Change Password", User: "PasswordManager", Date: 1700000000},
+		{Action: "Retrieve password", User: "brimstone", Reason: "HMSL Hash", Date: 1690000000},
+	}}
+	resbody, _ := json.Marshal(activities)
+	client := newRecordingStub(t, http.StatusOK, string(resbody), &requests)
+
+	fetched, err := client.FetchAccountActivities(context.Background(), "12_1")
+	assert.NoError(t, err)
+	assert.Equal(t, activities.Activities, fetched)
+	assert.Equal(t, "/PasswordVault/API/Accounts/12_1/Activities", requests[0].Path)
+}
@@ -1,14 +1,15 @@
 package privilegeaccessmanager
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/conjurdemos/cyberark-gitguardian-hmsl-remediation-integration-service/brimstone/pkg/utils"
@@ -22,6 +23,9 @@ type Config struct {
 	Pass            string
 	PlatformID      string
 	TLS_SKIP_VERIFY bool
+
+	// optional, wraps the transport of the PAM http requests, ex: to collect metrics
+	Transport func(http.RoundTripper) http.RoundTripper
 }
 
 type Session struct {
@@ -36,6 +40,9 @@ type Client struct {
 	AuthType string
 	Session  Session
 	Config   Config
+
+	// optional, shared session used instead of Session, see SessionManager.Client
+	Sessions *SessionManager
 }
 
 type Safe struct {
@@ -185,305 +192,226 @@ func NewConfig(idtenanturl string, pcloudurl string, safename string, platformid
 
 // NewClient - create a client with reasonable defaults
 func NewClient(baseurl string, config Config) Client {
-	sess := NewSession("", "", time.Now().Add(5*time.Hour)) // default 5hours for sesion token
 	client := Client{
 		BaseURL:  baseurl,
 		AuthType: "",
-		Session:  sess,
+		Session:  Session{}, // set by RefreshSessionToken, expires per the token expires_in
 		Config:   config,
 	}
 	return client
 }
 
-func (c *Client) FetchAccounts() ([]Account, error) {
-	var accounts []Account
-	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts/", c.Config.PCloudURL) // Use PCloud OAuth
-
+// httpClient - http client for the PAM requests
+func (c *Client) httpClient() *http.Client {
 	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
+	if c.Config.Transport != nil {
+		client.Transport = c.Config.Transport(client.Transport)
+	}
+	return client
+}
+
+// do - send the PAM request with the session token, if any; returns the response body and status,
+// an *APIError when PAM responds with an error status. With a session manager, a request rejected
+// with a 401 is sent again once with a new session token.
+func (c *Client) do(ctx context.Context, method string, apiurl string, contenttype string, body []byte) ([]byte, int, error) {
+	if c.Sessions == nil {
+		return c.send(ctx, method, apiurl, contenttype, body, c.Session)
+	}
+
+	session, err := c.Sessions.Session(ctx)
+	if err != nil {
+		return nil, StatusCode(err), err
+	}
+	resbody, code, err := c.send(ctx, method, apiurl, contenttype, body, session)
+	if code != http.StatusUnauthorized {
+		return resbody, code, err
+	}
+
+	// the token was revoked or expired early, authenticate again
+	c.Sessions.Invalidate(session)
+	session, err = c.Sessions.Session(ctx)
+	if err != nil {
+		return nil, StatusCode(err), err
+	}
+	return c.send(ctx, method, apiurl, contenttype, body, session)
+}
 
-	req, err := http.NewRequest(http.MethodGet, apiurl, nil)
+// send - send the PAM request with the session token, if any
+func (c *Client) send(ctx context.Context, method string, apiurl string, contenttype string, body []byte, session Session) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiurl, bytes.NewReader(body))
 	if err != nil {
-		return accounts, err
+		return nil, http.StatusConflict, err
 	}
 	// attach the header
 	req.Header = make(http.Header)
 	// if token is provided, add header Authorization
-	if c.Session.Token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("%s %s", c.Session.TokenType, c.Session.Token))
+	if session.Token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("%s %s", session.TokenType, session.Token))
 	}
-
-	res, err := client.Do(req)
-	if err != nil {
-		return accounts, fmt.Errorf("failed to send request. %s", err)
+	if contenttype != "" {
+		req.Header.Add("Content-Type", contenttype)
 	}
+	req.Header.Add("Accept", "application/json")
 
-	// read response body
-	body, error := io.ReadAll(res.Body)
-	if error != nil {
-		log.Println(error)
+	res, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("failed to send request. %w", err)
 	}
-	// close response body
 	defer res.Body.Close()
 
+	resbody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("failed to read response. %w", err)
+	}
 	if res.StatusCode >= 300 {
-		return accounts, fmt.Errorf("received non-200 status code '%d'", res.StatusCode)
+		return resbody, res.StatusCode, newAPIError(res.StatusCode, resbody)
 	}
+	return resbody, res.StatusCode, nil
+}
 
-	FetchAccountsResponse := FetchAccountsResponse{}
-	err = json.Unmarshal(body, &FetchAccountsResponse)
-
-	if err == nil {
-		for i := 0; i < len(FetchAccountsResponse.Value); i++ {
-			accounts = append(accounts, FetchAccountsResponse.Value[i])
-		}
+// doJSON - send the PAM request with the json encoded body
+func (c *Client) doJSON(ctx context.Context, method string, apiurl string, reqbody any) ([]byte, int, error) {
+	jsonbody, err := json.Marshal(reqbody)
+	if err != nil {
+		return nil, http.StatusConflict, fmt.Errorf("failed to encode request body: %w", err)
 	}
+	return c.do(ctx, method, apiurl, "application/json", jsonbody)
+}
 
-	return accounts, nil
+// FetchAccounts fetch every account the user can access, following nextLink through all the pages
+func (c *Client) FetchAccounts(ctx context.Context) ([]Account, error) {
+	return c.FetchAccountsWithOptions(ctx, FetchAccountsOptions{})
 }
 
-func (c *Client) GetSessionToken() (string, string, error) {
+// Authenticate - request a platform token from the identity tenant; the session expires per
+// the token expires_in
+func (c *Client) Authenticate(ctx context.Context) (Session, error) {
 	identurl := fmt.Sprintf("%s/oauth2/platformtoken", c.Config.IDTenantURL) // Use PCloud OAuth
 
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", c.Config.User)
 	data.Set("client_secret", c.Config.Pass)
-	encodedData := data.Encode()
 
-	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
-
-	req, err := http.NewRequest(http.MethodPost, identurl, strings.NewReader(encodedData))
-	if err != nil {
-		log.Fatalf("error in request to get session token: %s", err.Error())
+	body, code, err := c.send(ctx, http.MethodPost, identurl, "application/x-www-form-urlencoded", []byte(data.Encode()), Session{})
+	var apierr *APIError
+	if errors.As(err, &apierr) && apierr.Kind == ErrRequest {
+		// the identity tenant rejects bad client credentials with a 400
+		apierr.Kind = ErrAuth
 	}
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Add("Content-Length", strconv.Itoa(len(encodedData)))
-	response, err := client.Do(req)
-
-	body, e := io.ReadAll(response.Body)
-	if e != nil {
-		log.Fatalf("error reading platform token response: %s", err.Error())
+	if err != nil {
+		return Session{}, err
 	}
-	defer response.Body.Close()
 
 	var idresp IDTenantResponse
 	err = json.Unmarshal(body, &idresp)
 	if err != nil {
-		log.Fatalf("failed to parse json body for platform token: %s\n", err.Error())
+		return Session{}, fmt.Errorf("failed to parse json body for platform token: %w", err)
 	}
-
-	return idresp.TokenType, idresp.AccessToken, nil
-}
-func (c *Client) RefreshSessionToken() error {
-	var err error
-	c.Session.TokenType, c.Session.Token, err = c.GetSessionToken()
-	return err
-}
-
-// POST /api/Accounts/{accountId}/Password/Retrieve
-func (c *Client) FetchAccountPassword(accountid string) (string, error) {
-	var pass string
-	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts/%s/Password/Retrieve", c.Config.PCloudURL, accountid)
-
-	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
-
-	postbody := PostPasswordRetrieveRequest{
-		Reason: "HMSL Hash",
+	if idresp.AccessToken == "" {
+		return Session{}, &APIError{StatusCode: code, ErrorCode: idresp.Error, Message: idresp.ErrorDescription, Kind: ErrAuth}
 	}
 
-	jsonbody, err := json.Marshal(postbody)
-	if err != nil {
-		log.Fatalf("failed to parse json body for platform token: %s\n", err.Error())
+	lifetime := DEFAULT_SESSION_LIFETIME
+	if idresp.ExpiresIn > 0 {
+		lifetime = time.Duration(idresp.ExpiresIn) * time.Second
 	}
+	return NewSession(idresp.AccessToken, idresp.TokenType, time.Now().Add(lifetime)), nil
+}
 
-	req, err := http.NewRequest(http.MethodPost, apiurl, strings.NewReader(string(jsonbody)))
+// GetSessionToken - request a platform token from the identity tenant; returns the token type and token
+func (c *Client) GetSessionToken(ctx context.Context) (string, string, error) {
+	session, err := c.Authenticate(ctx)
 	if err != nil {
-		return pass, err
+		return "", "", err
 	}
-	// attach the header
-	req.Header = make(http.Header)
-	// if token is provided, add header Authorization
-	if c.Session.Token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("%s %s", c.Session.TokenType, c.Session.Token))
-	}
-	req.Header.Add("Content-Type", "application/json")
+	return session.TokenType, session.Token, nil
+}
 
-	res, err := client.Do(req)
+// RefreshSessionToken - authenticate and keep the session in the client
+func (c *Client) RefreshSessionToken(ctx context.Context) error {
+	session, err := c.Authenticate(ctx)
 	if err != nil {
-		return pass, fmt.Errorf("failed to send request. %s", err)
+		return err
 	}
+	c.Session = session
+	return nil
+}
 
-	// read response body
-	body, error := io.ReadAll(res.Body)
-	if error != nil {
-		log.Println(error)
-	}
-	// close response body
-	defer res.Body.Close()
+// POST /api/Accounts/{accountId}/Password/Retrieve
+func (c *Client) FetchAccountPassword(ctx context.Context, accountid string) (string, error) {
+	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts/%s/Password/Retrieve", c.Config.PCloudURL, accountid)
 
-	if res.StatusCode >= 300 {
-		return pass, fmt.Errorf("received non-200 status code '%d'", res.StatusCode)
+	postbody := PostPasswordRetrieveRequest{
+		Reason: "HMSL Hash",
 	}
 
-	// err = json.Unmarshal(body, &pass)
-	pass = utils.TrimQuotes(string(body))
-	return pass, nil
+	body, _, err := c.doJSON(ctx, http.MethodPost, apiurl, postbody)
+	if err != nil {
+		return "", err
+	}
+	return utils.TrimQuotes(string(body)), nil
 }
 
 // ChangePasswordImmediately -- https://docs.cyberark.com/PrivCloud-SS/latest/en/Content/WebServices/Change-credentials-immediately.htm
-func (c *Client) ChangePasswordImmediately(accountid string) (int, error) {
+func (c *Client) ChangePasswordImmediately(ctx context.Context, accountid string) (int, error) {
 
 	// POST /PasswordVault/API/Accounts/<AccountID>/Change/
 	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts/%s/Change", c.Config.PCloudURL, accountid)
 
-	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
-
 	postbody := PostChangePasswordImmediatelyRequest{
 		ChangeEntireGroup: true,
 	}
 
-	jsonbody, err := json.Marshal(postbody)
-	if err != nil {
-		log.Fatalf("failed to parse json body for platform token: %s\n", err.Error())
-	}
-
-	req, err := http.NewRequest(http.MethodPost, apiurl, strings.NewReader(string(jsonbody)))
+	body, code, err := c.doJSON(ctx, http.MethodPost, apiurl, postbody)
 	if err != nil {
-		return http.StatusConflict, err
+		return code, err
 	}
-	// attach the header
-	req.Header = make(http.Header)
-	// if token is provided, add header Authorization
-	if c.Session.Token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("%s %s", c.Session.TokenType, c.Session.Token))
-	}
-	req.Header.Add("Content-Type", "application/json")
-
-	res, err := client.Do(req)
-	if err != nil {
-		return http.StatusBadGateway, fmt.Errorf("failed to send request. %s", err)
-	}
-
-	// read response body
-	body, error := io.ReadAll(res.Body)
-	if error != nil {
-		log.Println(error)
-	}
-	// close response body
-	defer res.Body.Close()
 	log.Printf("%s\n", string(body))
 
-	if res.StatusCode >= 300 {
-		return res.StatusCode, fmt.Errorf("received non-200 status (code=%d): %s", res.StatusCode, body)
-	}
-
 	return http.StatusOK, nil
 }
 
 // AddAccount -- https://docs.cyberark.com/PrivCloud-SS/latest/en/Content/WebServices/Add%20Account%20v10.htm
-func (c *Client) AddAccount(postbody PostAddAccountRequest) (PostAddAccountResponse, int, error) {
+func (c *Client) AddAccount(ctx context.Context, postbody PostAddAccountRequest) (PostAddAccountResponse, int, error) {
 
 	var newacct PostAddAccountResponse
 
 	// POST /PasswordVault/API/Accounts/
 	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts/", c.Config.PCloudURL)
-	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
 
-	jsonbody, err := json.Marshal(postbody)
+	body, code, err := c.doJSON(ctx, http.MethodPost, apiurl, postbody)
 	if err != nil {
-		log.Fatalf("failed to parse json body for platform token: %s\n", err.Error())
+		return newacct, code, err
 	}
-
-	req, err := http.NewRequest(http.MethodPost, apiurl, strings.NewReader(string(jsonbody)))
-	if err != nil {
-		return newacct, http.StatusConflict, err
-	}
-	// attach the header
-	req.Header = make(http.Header)
-	// if token is provided, add header Authorization
-	if c.Session.Token != "" {
-		req.Header.Add("Authorization", fmt.Sprintf("%s %s", c.Session.TokenType, c.Session.Token))
-	}
-	req.Header.Add("Content-Type", "application/json")
-
-	res, err := client.Do(req)
-	if err != nil {
-		return newacct, http.StatusBadGateway, fmt.Errorf("failed to send request. %s", err)
-	}
-
-	// read response body
-	body, error := io.ReadAll(res.Body)
-	if error != nil {
-		log.Println(error)
-	}
-	// close response body
-	defer res.Body.Close()
 	log.Printf("%s\n", string(body))
 
 	err = json.Unmarshal(body, &newacct)
 	if err != nil {
-		if res.StatusCode >= 300 {
-			return newacct, res.StatusCode, fmt.Errorf("received non-200 status code(%d): %s", res.StatusCode, err.Error())
-		}
+		return newacct, http.StatusBadGateway, fmt.Errorf("failed to parse json body for new account: %w", err)
 	}
 
 	return newacct, http.StatusOK, nil
 }
 
-// FetchAccountIdFromAccountName fetch all accounts in the safe and iterate through list until accountname is found
-func (c *Client) FetchAccountIdFromAccountName(safename string, accountname string) (string, int, error) {
-
-	// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/SDK/GetAccounts.htm
-	filter := fmt.Sprintf("safeName eq %s", safename)
-	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts?filter=%s", c.Config.PCloudURL, url.QueryEscape(filter))
-
-	client := utils.GetHTTPClient(time.Second*30, c.Config.TLS_SKIP_VERIFY)
-
-	req, err := http.NewRequest(http.MethodGet, apiurl, nil)
-	if err != nil {
-		return "", http.StatusConflict, err
-	}
-	req.Header = make(http.Header)
-
-	// if token is provided, add header Authorization
-	authheader := "UNK"
-	if c.Session.Token != "" {
-		authheader = fmt.Sprintf("%s %s", c.Session.TokenType, c.Session.Token)
-		req.Header.Add("Authorization", authheader)
-	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-
-	res, err := client.Do(req)
-	if err != nil {
-		return "", http.StatusBadGateway, fmt.Errorf("failed to send request. %s", err)
-	}
-	log.Printf("APIURL: %s\n", apiurl)
-	// For debugging, emit an equivalent curl call, example...
-	// log.Printf("curl -XGET -H 'Authorization: %s' -H 'Accept: application/json' -H 'Content-Type: application/json' '%s'\n", authheader, apiurl)
-
-	body, error := io.ReadAll(res.Body)
-	if error != nil {
-		log.Println(error)
-	}
-	defer res.Body.Close()
-
-	log.Printf("%s\n", string(body))
-	var resp GetAccountsResponse
-	err = json.Unmarshal(body, &resp)
-
-	if res.StatusCode >= 300 {
-		return "", res.StatusCode, fmt.Errorf("received non-200 status (code=%d): %s", res.StatusCode, body)
-	}
-
-	foundid := ""
-	for i := 0; i < len(resp.Value); i++ {
-		if resp.Value[i].Name == accountname {
-			return resp.Value[i].ID, http.StatusOK, nil
+// FetchAccountIdFromAccountName fetch all accounts in the safe, page by page, and iterate through list until accountname is found
+func (c *Client) FetchAccountIdFromAccountName(ctx context.Context, safename string, accountname string) (string, int, error) {
+	apiurl := fmt.Sprintf("%s/PasswordVault/API/Accounts?%s", c.Config.PCloudURL, FetchAccountsOptions{SafeName: safename}.query().Encode())
+	for apiurl != "" {
+		log.Printf("APIURL: %s\n", apiurl)
+		resp, rescode, err := c.fetchAccountsPage(ctx, apiurl)
+		if err != nil {
+			return "", rescode, err
+		}
+		for i := 0; i < len(resp.Value); i++ {
+			if resp.Value[i].Name == accountname {
+				return resp.Value[i].ID, http.StatusOK, nil
+			}
+		}
+		apiurl, err = c.resolveNextLink(resp.NextLink)
+		if err != nil {
+			return "", http.StatusBadGateway, err
 		}
 	}
-	if len(foundid) == 0 {
-		return "", http.StatusNotFound, nil
-	}
-	return foundid, http.StatusOK, nil
+	return "", http.StatusNotFound, nil
 }
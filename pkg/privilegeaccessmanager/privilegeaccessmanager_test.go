@@ -0,0 +1,169 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPAMStub serves the handler as both the identity tenant and the vault
+func newPAMStub(t *testing.T, handler http.HandlerFunc) Client {
+	stub := httptest.NewServer(handler)
+	t.Cleanup(stub.Close)
+	config := NewConfig(stub.URL, stub.URL, "safe1", "DummyPlatform", "user", "pass", false)
+	return NewClient(stub.URL, config)
+}
+
+func TestGetSessionToken(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/oauth2/platformtoken", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		if r.PostForm.Get("client_id") != "user" || r.PostForm.Get("client_secret") != "pass" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+			return
+		}
+		w.Write([]byte(`{"token_type":"Bearer","access_token":"tok","expires_in":900}`))
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, client.RefreshSessionToken(ctx))
+	assert.Equal(t, "Bearer", client.Session.TokenType)
+	assert.Equal(t, "tok", client.Session.Token)
+
+	// bad credentials are an auth failure, the session is left unchanged
+	client.Config.Pass = "wrong"
+	err := client.RefreshSessionToken(ctx)
+	assert.ErrorIs(t, err, ErrAuth)
+	var apierr *APIError
+	assert.ErrorAs(t, err, &apierr)
+	assert.Equal(t, http.StatusBadRequest, apierr.StatusCode)
+	assert.Equal(t, "invalid_client", apierr.ErrorCode)
+	assert.Equal(t, "tok", client.Session.Token)
+}
+
+func TestGetSessionTokenNoToken(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":"access_denied"}`))
+	})
+	_, _, err := client.GetSessionToken(context.Background())
+	assert.ErrorIs(t, err, ErrAuth)
+}
+
+func TestGetSessionTokenUnreachable(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {})
+	client.Config.IDTenantURL = "http://127.0.0.1:1"
+
+	// a network error is returned, not a crash
+	_, _, err := client.GetSessionToken(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, StatusCode(err))
+}
+
+func TestFetchAccountPassword(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		var req PostPasswordRetrieveRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "HMSL Hash", req.Reason)
+		switch r.URL.Path {
+		case "/PasswordVault/API/Accounts/12_1/Password/Retrieve":
+			w.Write([]byte(`"s3cret"`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ErrorCode":"PASWS165E","ErrorMessage":"Account not found"}`))
+		}
+	})
+	client.Session = NewSession("tok", "Bearer", client.Session.Expiration)
+
+	ctx := context.Background()
+	pass, err := client.FetchAccountPassword(ctx, "12_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", pass)
+
+	_, err = client.FetchAccountPassword(ctx, "12_2")
+	assert.ErrorIs(t, err, ErrNotFound)
+	var apierr *APIError
+	assert.ErrorAs(t, err, &apierr)
+	assert.Equal(t, "PASWS165E", apierr.ErrorCode)
+	assert.Equal(t, "Account not found", apierr.Message)
+}
+
+func TestChangePasswordImmediately(t *testing.T) {
+	statuses := map[string]int{
+		"12_1": http.StatusOK,
+		"12_2": http.StatusTooManyRequests,
+		"12_3": http.StatusInternalServerError,
+		"12_4": http.StatusUnauthorized,
+	}
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		var req PostChangePasswordImmediatelyRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.ChangeEntireGroup)
+		for id, status := range statuses {
+			if r.URL.Path == "/PasswordVault/API/Accounts/"+id+"/Change" {
+				w.WriteHeader(status)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ctx := context.Background()
+	code, err := client.ChangePasswordImmediately(ctx, "12_1")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	code, err = client.ChangePasswordImmediately(ctx, "12_2")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+
+	code, err = client.ChangePasswordImmediately(ctx, "12_3")
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	_, err = client.ChangePasswordImmediately(ctx, "12_4")
+	assert.ErrorIs(t, err, ErrAuth)
+
+	// a cancelled request is not sent
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	code, err = client.ChangePasswordImmediately(cancelled, "12_1")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, http.StatusBadGateway, code)
+}
+
+func TestAddAccount(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/PasswordVault/API/Accounts/", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		var req PostAddAccountRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+		if req.PlatformID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ErrorCode":"PASWS027E","ErrorMessage":"The request is missing platformId"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(PostAddAccountResponse{ID: "12_9", Name: req.Name, SafeName: req.SafeName})
+	})
+
+	ctx := context.Background()
+	newacct, code, err := client.AddAccount(ctx, PostAddAccountRequest{Name: "acct", SafeName: "safe1", PlatformID: "DummyPlatform"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "12_9", newacct.ID)
+
+	// a rejected account is an error, not an empty account
+	newacct, code, err = client.AddAccount(ctx, PostAddAccountRequest{Name: "acct", SafeName: "safe1"})
+	assert.ErrorIs(t, err, ErrRequest)
+	assert.ErrorContains(t, err, "PASWS027E")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "", newacct.ID)
+}
@@ -0,0 +1,65 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SafeMember - user or group member of a safe, with its safe permissions
+type SafeMember struct {
+	SafeUrlId                 string          `json:"safeUrlId,omitempty"`
+	SafeName                  string          `json:"safeName,omitempty"`
+	SafeNumber                int64           `json:"safeNumber,omitempty"`
+	MemberID                  string          `json:"memberId,omitempty"`
+	MemberName                string          `json:"memberName,omitempty"`
+	MemberType                string          `json:"memberType,omitempty"`
+	MembershipExpirationDate  int64           `json:"membershipExpirationDate,omitempty"`
+	IsExpiredMembershipEnable bool            `json:"isExpiredMembershipEnable,omitempty"`
+	IsPredefinedUser          bool            `json:"isPredefinedUser,omitempty"`
+	IsReadOnly                bool            `json:"isReadOnly,omitempty"`
+	Permissions               map[string]bool `json:"permissions,omitempty"`
+}
+
+// listResponse - page of a PAM list, followed with nextLink
+type listResponse[T any] struct {
+	Value    []T    `json:"value"`
+	Count    int    `json:"count,omitempty"`
+	NextLink string `json:"nextLink,omitempty"`
+}
+
+// fetchAll - every item of the PAM list, all pages
+func fetchAll[T any](ctx context.Context, c *Client, apiurl string) ([]T, error) {
+	var items []T
+	for apiurl != "" {
+		var page listResponse[T]
+		body, _, err := c.do(ctx, http.MethodGet, apiurl, "", nil)
+		if err != nil {
+			return items, err
+		}
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return items, fmt.Errorf("failed to parse list response: %w", err)
+		}
+		items = append(items, page.Value...)
+		apiurl, err = c.resolveNextLink(page.NextLink)
+		if err != nil {
+			return items, err
+		}
+	}
+	return items, nil
+}
+
+// FetchSafes - GET /PasswordVault/API/Safes, the safes the user is a member of
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/WebServices/Safes%20Web%20Services%20-%20List%20Safes.htm
+func (c *Client) FetchSafes(ctx context.Context) ([]Safe, error) {
+	return fetchAll[Safe](ctx, c, fmt.Sprintf("%s/PasswordVault/API/Safes", c.Config.PCloudURL))
+}
+
+// FetchSafeMembers - GET /PasswordVault/API/Safes/{safeUrlId}/Members
+// https://docs.cyberark.com/PrivCloud-SS/Latest/en/Content/WebServices/Safe%20Members%20WS%20-%20List%20Safe%20Members.htm
+func (c *Client) FetchSafeMembers(ctx context.Context, safename string) ([]SafeMember, error) {
+	return fetchAll[SafeMember](ctx, c, fmt.Sprintf("%s/PasswordVault/API/Safes/%s/Members", c.Config.PCloudURL, url.PathEscape(safename)))
+}
@@ -0,0 +1,50 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchSafes(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "" {
+			assert.Equal(t, "/PasswordVault/API/Safes", r.URL.Path)
+			w.Write([]byte(`{"value":[{"safeName":"safe1"},{"safeName":"safe2"}],"count":3,"nextLink":"api/Safes?offset=2&limit=2"}`))
+			return
+		}
+		// nextLink is relative to /PasswordVault/
+		assert.Equal(t, "/PasswordVault/api/Safes", r.URL.Path)
+		w.Write([]byte(`{"value":[{"safeName":"safe3"}],"count":3}`))
+	})
+
+	safes, err := client.FetchSafes(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, safes, 3)
+	assert.Equal(t, "safe3", safes[2].SafeName)
+}
+
+func TestFetchSafeMembers(t *testing.T) {
+	client := newPAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/PasswordVault/API/Safes/safe%201/Members" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ErrorCode":"SFWS0007","ErrorMessage":"Safe not found"}`))
+			return
+		}
+		w.Write([]byte(`{"value":[{"safeName":"safe 1","memberName":"brimstone","memberType":"User","permissions":{"useAccounts":true,"retrieveAccounts":true,"deleteAccounts":false}}],"count":1}`))
+	})
+
+	ctx := context.Background()
+	members, err := client.FetchSafeMembers(ctx, "safe 1")
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+	assert.Equal(t, "brimstone", members[0].MemberName)
+	assert.True(t, members[0].Permissions["retrieveAccounts"])
+	assert.False(t, members[0].Permissions["deleteAccounts"])
+
+	_, err = client.FetchSafeMembers(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "SFWS0007")
+}
@@ -0,0 +1,71 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// the session token is refreshed this long before it expires
+const SESSION_REFRESH_MARGIN = time.Minute
+
+// lifetime of the session token when the identity tenant does not send expires_in
+const DEFAULT_SESSION_LIFETIME = 5 * time.Minute
+
+// SessionManager shares one PAM session token between goroutines; the token is requested when
+// first needed and refreshed before it expires, or when PAM rejects it
+type SessionManager struct {
+	config Config
+
+	mu      sync.Mutex
+	session Session
+	now     func() time.Time
+}
+
+// NewSessionManager - session manager authenticating with the PAM config
+func NewSessionManager(config Config) *SessionManager {
+	return &SessionManager{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Config - the PAM config of the sessions
+func (m *SessionManager) Config() Config {
+	return m.config
+}
+
+// Client - client sending its requests with the managed session
+func (m *SessionManager) Client() Client {
+	client := NewClient(m.config.PCloudURL, m.config)
+	client.Sessions = m
+	return client
+}
+
+// Session - a valid session, authenticating again when there is none or it is about to expire;
+// concurrent callers wait for a single refresh
+func (m *SessionManager) Session(ctx context.Context) (Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session.Token != "" && m.now().Add(SESSION_REFRESH_MARGIN).Before(m.session.Expiration) {
+		return m.session, nil
+	}
+	client := NewClient(m.config.PCloudURL, m.config)
+	session, err := client.Authenticate(ctx)
+	if err != nil {
+		return Session{}, err
+	}
+	m.session = session
+	return m.session, nil
+}
+
+// Invalidate - drop the session PAM rejected, unless it was already refreshed by another request
+func (m *SessionManager) Invalidate(session Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session.Token == session.Token {
+		m.session = Session{}
+	}
+}
@@ -0,0 +1,120 @@
+package privilegeaccessmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSessionStub - identity tenant issuing tokens tok1, tok2, ... valid for expiresin seconds, and a
+// vault accepting only the last token issued; counts the tokens issued
+func newSessionStub(t *testing.T, expiresin int, issued *int32) *SessionManager {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/platformtoken" {
+			n := atomic.AddInt32(issued, 1)
+			w.Write([]byte(fmt.Sprintf(`{"token_type":"Bearer","access_token":"tok%d","expires_in":%d}`, n, expiresin)))
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer tok%d", atomic.LoadInt32(issued)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(stub.Close)
+	return NewSessionManager(NewConfig(stub.URL, stub.URL, "safe1", "DummyPlatform", "user", "pass", false))
+}
+
+func TestSessionManagerExpiresIn(t *testing.T) {
+	var issued int32
+	sessions := newSessionStub(t, 600, &issued)
+	now := time.Now()
+	sessions.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	session, err := sessions.Session(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "tok1", session.Token)
+	assert.WithinDuration(t, time.Now().Add(600*time.Second), session.Expiration, 5*time.Second)
+
+	// reused while valid
+	session, err = sessions.Session(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "tok1", session.Token)
+
+	// refreshed before it expires
+	now = session.Expiration.Add(-SESSION_REFRESH_MARGIN / 2)
+	session, err = sessions.Session(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "tok2", session.Token)
+	assert.Equal(t, int32(2), issued)
+}
+
+func TestSessionManagerConcurrent(t *testing.T) {
+	var issued int32
+	sessions := newSessionStub(t, 600, &issued)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := sessions.Client()
+			_, err := client.ChangePasswordImmediately(context.Background(), "12_1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), issued)
+}
+
+func TestSessionManagerRetryUnauthorized(t *testing.T) {
+	var issued int32
+	sessions := newSessionStub(t, 600, &issued)
+	client := sessions.Client()
+
+	ctx := context.Background()
+	_, err := sessions.Session(ctx)
+	assert.NoError(t, err)
+
+	// the token is revoked: another token was issued since
+	_, err = (&Client{Config: sessions.Config()}).Authenticate(ctx)
+	assert.NoError(t, err)
+
+	code, err := client.ChangePasswordImmediately(ctx, "12_1")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(3), issued)
+}
+
+func TestSessionManagerRetryOnce(t *testing.T) {
+	var issued int32
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/platformtoken" {
+			atomic.AddInt32(&issued, 1)
+			w.Write([]byte(`{"token_type":"Bearer","access_token":"tok"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(stub.Close)
+	sessions := NewSessionManager(NewConfig(stub.URL, stub.URL, "safe1", "DummyPlatform", "user", "pass", false))
+	client := sessions.Client()
+
+	code, err := client.ChangePasswordImmediately(context.Background(), "12_1")
+	assert.ErrorIs(t, err, ErrAuth)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, int32(2), issued)
+
+	// without expires_in the default lifetime is used
+	session, err := sessions.Session(context.Background())
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DEFAULT_SESSION_LIFETIME), session.Expiration, 5*time.Second)
+}